@@ -0,0 +1,133 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// isArchive returns true if file is a tar, gzip compressed tar or zip archive
+func isArchive(file string) bool {
+	return isTarArchive(file) || isTarGzipArchive(file) || isZipArchive(file)
+}
+
+func isTarArchive(file string) bool {
+	return strings.HasSuffix(file, ".tar")
+}
+
+func isTarGzipArchive(file string) bool {
+	return strings.HasSuffix(file, ".tar.gz") || strings.HasSuffix(file, ".tgz")
+}
+
+func isZipArchive(file string) bool {
+	return strings.HasSuffix(file, ".zip")
+}
+
+// listArchiveFiles opens archive into a memory backed filesystem and lists the YAML files it
+// contains. Files are named using the path of the archive and the path of the entry within it
+func (o *options) listArchiveFiles(fs afero.Fs, archive string) ([]string, error) {
+	archiveFs, err := readArchive(fs, archive)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read archive %s", archive)
+	}
+
+	files, err := o.addInputFs(archive, archiveFs, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list archive %s", archive)
+	}
+	return files, nil
+}
+
+// readArchive reads the regular files in archive into a memory backed filesystem
+func readArchive(fs afero.Fs, archive string) (afero.Fs, error) {
+	b, err := afero.ReadFile(fs, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveFs := afero.NewMemMapFs()
+	switch {
+	case isZipArchive(archive):
+		err = readZipArchive(b, archiveFs)
+	case isTarGzipArchive(archive):
+		var r *gzip.Reader
+		r, err = gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		err = readTarArchive(r, archiveFs)
+	default:
+		err = readTarArchive(bytes.NewReader(b), archiveFs)
+	}
+	return archiveFs, err
+}
+
+func readTarArchive(r io.Reader, archiveFs afero.Fs) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		err = writeArchiveEntry(archiveFs, header.Name, tr)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readZipArchive(b []byte, archiveFs afero.Fs) error {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return err
+	}
+	for _, file := range zr.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = writeArchiveEntry(archiveFs, file.Name, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchiveEntry writes an archive entry into the archive filesystem. Entry names are cleaned so
+// that they cannot refer to paths outside of the archive root
+func writeArchiveEntry(archiveFs afero.Fs, name string, r io.Reader) error {
+	name = path.Clean(inputFsRoot + strings.TrimPrefix(filepath.ToSlash(name), "./"))
+
+	err := archiveFs.MkdirAll(path.Dir(name), defaultDirectoryPerms)
+	if err != nil {
+		return err
+	}
+	f, err := archiveFs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultFilePerms)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
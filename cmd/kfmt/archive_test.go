@@ -0,0 +1,113 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveEntries are the entries written to test archives
+var archiveEntries = []struct {
+	name     string
+	contents string
+}{
+	{"bundle/namespace.yaml", `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`},
+	{"bundle/manifests/configmap.json", `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "test", "namespace": "test"}}`},
+	{"bundle/README.md", "# Bundle\n"},
+}
+
+func newTarGzipArchive(t *testing.T) []byte {
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gw)
+	for _, entry := range archiveEntries {
+		err := tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.contents)), Typeflag: tar.TypeReg})
+		require.Nil(t, err)
+		_, err = tw.Write([]byte(entry.contents))
+		require.Nil(t, err)
+	}
+	require.Nil(t, tw.Close())
+	require.Nil(t, gw.Close())
+	return b.Bytes()
+}
+
+func newZipArchive(t *testing.T) []byte {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, entry := range archiveEntries {
+		w, err := zw.Create(entry.name)
+		require.Nil(t, err)
+		_, err = w.Write([]byte(entry.contents))
+		require.Nil(t, err)
+	}
+	require.Nil(t, zw.Close())
+	return b.Bytes()
+}
+
+func TestArchives(t *testing.T) {
+	for archive, contents := range map[string][]byte{
+		"bundle.tgz": newTarGzipArchive(t),
+		"bundle.zip": newZipArchive(t),
+	} {
+		// Setup options
+		o := &options{
+			inputs:  []string{archive},
+			output:  outputDirectory,
+			comment: true,
+			remove:  true,
+		}
+
+		// Setup memory backed filesystem
+		fs := afero.NewMemMapFs()
+		err := afero.WriteFile(fs, archive, contents, 0644)
+		require.Nil(t, err)
+
+		// Check that each manifest in the archive is organised and commented with its path in the
+		// archive
+		err = o.run(fs)
+		require.Nil(t, err)
+		err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "namespaces/test.yaml"), `---
+# Source: `+archive+`:bundle/namespace.yaml
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`)
+		require.Nil(t, err)
+		err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-test.yaml"), `---
+# Source: `+archive+`:bundle/manifests/configmap.json
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: test
+`)
+		require.Nil(t, err)
+
+		// Check that archives are not removed
+		err = requireRegularFileContents(fs, archive, string(contents))
+		require.Nil(t, err)
+	}
+
+	// Check that invalid archives are reported
+	o := &options{
+		inputs: []string{"invalid.tgz"},
+		output: outputDirectory,
+	}
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "invalid.tgz", []byte("invalid"), 0644)
+	require.Nil(t, err)
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to read archive invalid.tgz: unexpected EOF")
+}
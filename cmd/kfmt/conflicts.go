@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dippynark/kfmt/pkg/discovery"
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Fail if scope definitions conflict
+	scopeConflictPolicyError = "error"
+	// Warn if scope definitions conflict and use the first discovery source
+	scopeConflictPolicyWarn = "warn"
+	// Use the scope from the named discovery source if scope definitions conflict (e.g. prefer-crds)
+	scopeConflictPolicyPreferPrefix = "prefer-"
+)
+
+// scopeDefinition records a GVK scope and where it was defined
+type scopeDefinition struct {
+	namespaced bool
+	// source is the name of the discovery source containing the definition
+	source string
+	// origin optionally identifies the definition within the source
+	origin string
+}
+
+func (d scopeDefinition) String() string {
+	scope := apiextensions.ClusterScoped
+	if d.namespaced {
+		scope = apiextensions.NamespaceScoped
+	}
+	if d.origin != "" {
+		return fmt.Sprintf("%s from %s (%s)", scope, d.source, d.origin)
+	}
+	return fmt.Sprintf("%s from %s", scope, d.source)
+}
+
+// scopeDefinitions maps each GVK to every scope definition found
+type scopeDefinitions map[schema.GroupVersionKind][]scopeDefinition
+
+func (s scopeDefinitions) add(gvk schema.GroupVersionKind, definition scopeDefinition) {
+	s[gvk] = append(s[gvk], definition)
+}
+
+// conflicting returns true if the definitions of gvk disagree about scope
+func (s scopeDefinitions) conflicting(gvk schema.GroupVersionKind) bool {
+	for _, definition := range s[gvk] {
+		if definition.namespaced != s[gvk][0].namespaced {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveScopeConflicts compares the scope definitions found in CRDs and GVK scopes with each other
+// and with the remaining discovery sources and applies the scope conflict policy to any conflicts
+func (o *options) resolveScopeConflicts(definitions scopeDefinitions, resourceInspector *discovery.ChainResourceInspector) error {
+	return resolveScopeConflicts(o.scopeConflictPolicy, definitions, resourceInspector, os.Stderr)
+}
+
+func resolveScopeConflicts(policy string, definitions scopeDefinitions, resourceInspector *discovery.ChainResourceInspector, warnings io.Writer) error {
+	if policy == "" {
+		policy = scopeConflictPolicyWarn
+	}
+	preferredSource := ""
+	switch {
+	case policy == scopeConflictPolicyError, policy == scopeConflictPolicyWarn:
+	case strings.HasPrefix(policy, scopeConflictPolicyPreferPrefix) && isDiscoverySource(strings.TrimPrefix(policy, scopeConflictPolicyPreferPrefix)):
+		preferredSource = strings.TrimPrefix(policy, scopeConflictPolicyPreferPrefix)
+	default:
+		return errors.Errorf("unrecognised scope conflict policy %s", policy)
+	}
+
+	var gvks []schema.GroupVersionKind
+	for gvk := range definitions {
+		gvks = append(gvks, gvk)
+	}
+	sort.Slice(gvks, func(i, j int) bool {
+		return gvks[i].String() < gvks[j].String()
+	})
+
+	var conflicts []string
+	for _, gvk := range gvks {
+		// Compare with the remaining discovery sources
+		for _, name := range resourceInspector.Order() {
+			if name == discovery.CRDSource || name == discovery.GVKScopeSource {
+				continue
+			}
+			source := resourceInspector.Source(name)
+			if source == nil {
+				continue
+			}
+			namespaced, err := source.IsNamespaced(gvk)
+			if err != nil {
+				continue
+			}
+			definitions.add(gvk, scopeDefinition{
+				namespaced: namespaced,
+				source:     name,
+			})
+		}
+
+		if !definitions.conflicting(gvk) {
+			continue
+		}
+
+		var descriptions []string
+		for _, definition := range definitions[gvk] {
+			descriptions = append(descriptions, definition.String())
+		}
+		conflict := fmt.Sprintf("conflicting scope definitions for %s: %s", gvk.String(), strings.Join(descriptions, ", "))
+
+		if preferredSource != "" {
+			// Override the scope with the last definition from the preferred source
+			found := false
+			for _, definition := range definitions[gvk] {
+				if definition.source == preferredSource {
+					resourceInspector.AddGVKToScope(gvk, definition.namespaced)
+					found = true
+				}
+			}
+			if found {
+				continue
+			}
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if policy == scopeConflictPolicyError {
+		return errors.New(strings.Join(conflicts, "\n"))
+	}
+	for _, conflict := range conflicts {
+		fmt.Fprintf(warnings, "warning: %s\n", conflict)
+	}
+	return nil
+}
+
+// resolveClusterScopeConflicts applies the scope conflict policy to GVKs whose scope differs between
+// the clusters used for discovery. The scope served by the first cluster is used unless the policy
+// is to fail
+func (o *options) resolveClusterScopeConflicts(resourceInspector *discovery.ChainResourceInspector) error {
+	return resolveClusterScopeConflicts(o.scopeConflictPolicy, resourceInspector, os.Stderr)
+}
+
+func resolveClusterScopeConflicts(policy string, resourceInspector *discovery.ChainResourceInspector, warnings io.Writer) error {
+	apiServerResourceInspector, ok := resourceInspector.Source(discovery.APIServerSource).(*discovery.APIServerResourceInspector)
+	if !ok {
+		return nil
+	}
+
+	var conflicts []string
+	for _, conflict := range apiServerResourceInspector.Conflicts() {
+		conflicts = append(conflicts, conflict.String())
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if policy == scopeConflictPolicyError {
+		return errors.New(strings.Join(conflicts, "\n"))
+	}
+	for _, conflict := range conflicts {
+		fmt.Fprintf(warnings, "warning: %s\n", conflict)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dippynark/kfmt/pkg/discovery"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kdiscov "k8s.io/client-go/discovery"
+)
+
+type discoveryDumpOptions struct {
+	output string
+}
+
+func newDiscoveryCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "discovery",
+		Short: "Manage discovery information used to organise manifests",
+	}
+
+	cmd.AddCommand(newDiscoveryDumpCommand(o))
+
+	return cmd
+}
+
+func newDiscoveryDumpCommand(o *options) *cobra.Command {
+	d := &discoveryDumpOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Write a snapshot of API Server discovery information that can be passed to --api-resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.contexts) > 1 {
+				return errors.New("discovery snapshots can only be written for a single context")
+			}
+			clusters, err := o.getClusters()
+			if err != nil {
+				return err
+			}
+			client, err := kdiscov.NewDiscoveryClientForConfig(clusters[0].Config)
+			if err != nil {
+				return errors.Wrap(err, "failed to construct discovery client")
+			}
+			return d.run(afero.NewOsFs(), client, os.Stderr)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVarP(&d.output, "output", "o", "", "File to write the discovery snapshot to. If no output is specified stdout will be used")
+
+	return cmd
+}
+
+func (d *discoveryDumpOptions) run(fs afero.Fs, client kdiscov.DiscoveryInterface, warnings io.Writer) error {
+	snapshot, err := discovery.NewSnapshot(client)
+	if kdiscov.IsGroupDiscoveryFailedError(err) {
+		// Write the group versions that could be discovered so that snapshots can be taken while
+		// aggregated API Servers are unavailable
+		reportFailedGroups(err.(*kdiscov.ErrGroupDiscoveryFailed), warnings)
+	} else if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if d.output != "" {
+		err = fs.MkdirAll(filepath.Dir(d.output), defaultDirectoryPerms)
+		if err != nil {
+			return err
+		}
+		file, err := fs.OpenFile(d.output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultFilePerms)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return errors.Wrap(snapshot.Write(w), "failed to write discovery snapshot")
+}
+
+// reportFailedGroups warns about each group version that is missing from a discovery snapshot
+func reportFailedGroups(err *kdiscov.ErrGroupDiscoveryFailed, w io.Writer) {
+	var groupVersions []schema.GroupVersion
+	for gv := range err.Groups {
+		groupVersions = append(groupVersions, gv)
+	}
+	sort.Slice(groupVersions, func(i, j int) bool {
+		return groupVersions[i].String() < groupVersions[j].String()
+	})
+
+	for _, gv := range groupVersions {
+		fmt.Fprintf(w, "warning: failed to discover %s, its resources are not included in the discovery snapshot: %s\n", gv.String(), err.Groups[gv])
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeversion "k8s.io/apimachinery/pkg/version"
+	kdiscov "k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+// partialDiscovery simulates an API Server whose aggregated group versions cannot be discovered
+type partialDiscovery struct {
+	*fake.FakeDiscovery
+	failed map[schema.GroupVersion]error
+}
+
+func (p *partialDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, p.Resources, &kdiscov.ErrGroupDiscoveryFailed{Groups: p.failed}
+}
+
+func TestDiscoveryDump(t *testing.T) {
+	// Setup options
+	d := &discoveryDumpOptions{
+		output: "discovery/snapshot.yaml",
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Setup fake discovery client with unsorted resources
+	client := &fake.FakeDiscovery{
+		Fake: &kubetesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "test.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "testers", SingularName: "tester", Namespaced: false, Kind: "Tester", Verbs: []string{"get"}},
+					},
+				},
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "secrets", SingularName: "secret", Namespaced: true, Kind: "Secret", Verbs: []string{"get"}},
+						{Name: "configmaps", SingularName: "configmap", Namespaced: true, Kind: "ConfigMap", Verbs: []string{"get"}, ShortNames: []string{"cm"}},
+					},
+				},
+			},
+		},
+		FakedServerVersion: &kubeversion.Info{GitVersion: "v1.20.2"},
+	}
+
+	// Dump discovery information
+	var warnings bytes.Buffer
+	err := d.run(fs, client, &warnings)
+	require.Nil(t, err)
+	require.Equal(t, warnings.String(), "")
+
+	// Ensure snapshot is sorted and versioned
+	err = requireRegularFileContents(fs, "discovery/snapshot.yaml", `apiVersion: kfmt.dev/v1alpha1
+kind: DiscoverySnapshot
+resources:
+- groupVersion: v1
+  resources:
+  - kind: ConfigMap
+    name: configmaps
+    namespaced: true
+    shortNames:
+    - cm
+    singularName: configmap
+    verbs:
+    - get
+  - kind: Secret
+    name: secrets
+    namespaced: true
+    singularName: secret
+    verbs:
+    - get
+- groupVersion: test.io/v1
+  resources:
+  - kind: Tester
+    name: testers
+    namespaced: false
+    singularName: tester
+    verbs:
+    - get
+serverVersion: v1.20.2
+`)
+	require.Nil(t, err)
+}
+
+func TestDiscoveryDumpPartial(t *testing.T) {
+	// Setup options
+	d := &discoveryDumpOptions{
+		output: "discovery/snapshot.yaml",
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Setup fake discovery client that fails to discover aggregated group versions
+	client := &partialDiscovery{
+		FakeDiscovery: &fake.FakeDiscovery{
+			Fake: &kubetesting.Fake{
+				Resources: []*metav1.APIResourceList{
+					{
+						GroupVersion: "v1",
+						APIResources: []metav1.APIResource{
+							{Name: "configmaps", SingularName: "configmap", Namespaced: true, Kind: "ConfigMap", Verbs: []string{"get"}},
+						},
+					},
+				},
+			},
+			FakedServerVersion: &kubeversion.Info{GitVersion: "v1.20.2"},
+		},
+		failed: map[schema.GroupVersion]error{
+			{Group: "metrics.k8s.io", Version: "v1beta1"}:        errors.New("the server is currently unable to handle the request"),
+			{Group: "custom.metrics.k8s.io", Version: "v1beta1"}: errors.New("the server is currently unable to handle the request"),
+		},
+	}
+
+	// Dump discovery information
+	var warnings bytes.Buffer
+	err := d.run(fs, client, &warnings)
+	require.Nil(t, err)
+
+	// Ensure the group versions that were discovered are written and the failed group versions are
+	// reported
+	err = requireRegularFileContents(fs, "discovery/snapshot.yaml", `apiVersion: kfmt.dev/v1alpha1
+kind: DiscoverySnapshot
+resources:
+- groupVersion: v1
+  resources:
+  - kind: ConfigMap
+    name: configmaps
+    namespaced: true
+    singularName: configmap
+    verbs:
+    - get
+serverVersion: v1.20.2
+`)
+	require.Nil(t, err)
+	require.Equal(t, warnings.String(), `warning: failed to discover custom.metrics.k8s.io/v1beta1, its resources are not included in the discovery snapshot: the server is currently unable to handle the request
+warning: failed to discover metrics.k8s.io/v1beta1, its resources are not included in the discovery snapshot: the server is currently unable to handle the request
+`)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// Prefix of inputs read from a git revision of a local repository
+	gitInputPrefix = "git::"
+	// Separates the repository path from the revision of git inputs
+	gitRevisionSeparator = "@"
+)
+
+// gitInput is a directory at a revision of a local git repository specified as
+// git::<repo-path>@<ref>[:<subdir>]
+type gitInput struct {
+	repository string
+	revision   string
+	dir        string
+}
+
+// isGitInput returns true if input refers to a git revision
+func isGitInput(input string) bool {
+	return strings.HasPrefix(input, gitInputPrefix)
+}
+
+// parseGitInput parses an input of the form git::<repo-path>@<ref>[:<subdir>]. The revision may be
+// any revision understood by git rev-parse that go-git supports (e.g. origin/main, v1.0.0 or HEAD~1)
+func parseGitInput(input string) (gitInput, error) {
+	spec := strings.TrimPrefix(input, gitInputPrefix)
+	i := strings.LastIndex(spec, gitRevisionSeparator)
+	if i <= 0 || i == len(spec)-1 {
+		return gitInput{}, errors.Errorf("invalid git input %s, expected %s<repo-path>%s<ref>[%s<subdir>]", input, gitInputPrefix, gitRevisionSeparator, inputFsSeparator)
+	}
+
+	g := gitInput{repository: spec[:i], revision: spec[i+1:]}
+	if j := strings.Index(g.revision, inputFsSeparator); j >= 0 {
+		g.dir = strings.Trim(path.Clean("/"+g.revision[j+1:]), "/")
+		g.revision = g.revision[:j]
+	}
+	if g.revision == "" {
+		return gitInput{}, errors.Errorf("invalid git input %s, revision not specified", input)
+	}
+	return g, nil
+}
+
+// name returns the name of the input filesystem containing the files of the revision
+func (g gitInput) name() string {
+	return gitInputPrefix + g.repository + gitRevisionSeparator + g.revision
+}
+
+// gitRevision is a revision of a git repository whose directories are read into a memory backed
+// filesystem when they are first used by a git input
+type gitRevision struct {
+	tree *object.Tree
+	fs   afero.Fs
+	// dirs contains the directories that have been read
+	dirs map[string]struct{}
+}
+
+// listGitFiles reads the directory of a git input at its revision into a read-only filesystem and
+// lists the YAML files it contains. Directories of the same revision share a filesystem. Files are
+// named using the repository, the revision and the path of the file within the repository (e.g.
+// git::.@origin/main:manifests/deployment.yaml)
+func (o *options) listGitFiles(input string) ([]string, error) {
+	g, err := parseGitInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, ok := o.gitRevisions[g.name()]
+	if !ok {
+		revision, err = openGitRevision(g)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read git input %s", input)
+		}
+		if o.gitRevisions == nil {
+			o.gitRevisions = map[string]*gitRevision{}
+		}
+		o.gitRevisions[g.name()] = revision
+	}
+	err = revision.readDir(g.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read git input %s", input)
+	}
+
+	files, err := o.addInputFs(g.name(), afero.NewReadOnlyFs(revision.fs), g.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list git input %s", input)
+	}
+	return files, nil
+}
+
+// openGitRevision resolves the revision of a git input
+func openGitRevision(g gitInput) (*gitRevision, error) {
+	repository, err := git.PlainOpenWithOptions(g.repository, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open repository %s", g.repository)
+	}
+	hash, err := repository.ResolveRevision(plumbing.Revision(g.revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve revision %s", g.revision)
+	}
+	commit, err := repository.CommitObject(*hash)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	return &gitRevision{tree: tree, fs: afero.NewMemMapFs(), dirs: map[string]struct{}{}}, nil
+}
+
+// readDir reads the YAML, JSON and ignore files in dir into the filesystem of the revision. Files
+// keep their path within the repository
+func (r *gitRevision) readDir(dir string) error {
+	if _, ok := r.dirs[dir]; ok {
+		return nil
+	}
+
+	tree := r.tree
+	if dir != "" {
+		var err error
+		tree, err = r.tree.Tree(dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find directory %s", dir)
+		}
+	}
+
+	err := r.fs.MkdirAll(path.Join(inputFsRoot, dir), defaultDirectoryPerms)
+	if err != nil {
+		return err
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		// Ignore symlinks, submodules and files that cannot be listed
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable {
+			return nil
+		}
+		if !isYAMLFile(f.Name) && path.Base(f.Name) != ignoreFileName {
+			return nil
+		}
+		name := path.Join(inputFsRoot, dir, f.Name)
+
+		reader, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		err = r.fs.MkdirAll(path.Dir(name), defaultDirectoryPerms)
+		if err != nil {
+			return err
+		}
+		w, err := r.fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultFilePerms)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+		_, err = io.Copy(w, reader)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.dirs[dir] = struct{}{}
+	return nil
+}
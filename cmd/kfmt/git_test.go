@@ -0,0 +1,148 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFiles writes files to the worktree of repository and commits them
+func commitFiles(t *testing.T, repository *git.Repository, dir string, files map[string]string) {
+	worktree, err := repository.Worktree()
+	require.Nil(t, err)
+	for file, contents := range files {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755)
+		require.Nil(t, err)
+		err = ioutil.WriteFile(filepath.Join(dir, file), []byte(contents), 0644)
+		require.Nil(t, err)
+		_, err = worktree.Add(file)
+		require.Nil(t, err)
+	}
+	_, err = worktree.Commit("Update manifests", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.Nil(t, err)
+}
+
+func TestGitInput(t *testing.T) {
+	// Create a repository whose manifests are changed after the first commit
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	require.Nil(t, err)
+	commitFiles(t, repository, dir, map[string]string{
+		"manifests/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  revision: first
+`,
+		"manifests/NOTES.txt": "Test\n",
+		"README.md":           "# Test\n",
+	})
+	commitFiles(t, repository, dir, map[string]string{
+		"manifests/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  revision: second
+`,
+	})
+
+	// Setup options
+	o := &options{
+		inputs:  []string{"git::" + dir + "@HEAD~1:manifests"},
+		output:  outputDirectory,
+		comment: true,
+		remove:  true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Check that manifests are read from the revision and attributed to their path in the repository
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-test.yaml"), `---
+# Source: git::`+dir+`@HEAD~1:manifests/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+data:
+  revision: first
+`)
+	require.Nil(t, err)
+
+	// Check that the repository is not changed by --remove
+	b, err := ioutil.ReadFile(filepath.Join(dir, "manifests/configmap.yaml"))
+	require.Nil(t, err)
+	require.Contains(t, string(b), "revision: second")
+
+	// Check that several directories of the same revision are read
+	commitFiles(t, repository, dir, map[string]string{
+		"other/secret.yaml": `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+`,
+	})
+	o.inputs = []string{"git::" + dir + "@HEAD:manifests", "git::" + dir + "@HEAD:other"}
+	yamlFiles, err := o.findYAMLFiles(fs)
+	require.Nil(t, err)
+	require.Equal(t, yamlFiles, []string{"git::" + dir + "@HEAD:manifests/configmap.yaml", "git::" + dir + "@HEAD:other/secret.yaml"})
+	yamlFileNodes, err := o.findYAMLFileNodes(fs, yamlFiles)
+	require.Nil(t, err)
+	require.Len(t, yamlFileNodes["git::"+dir+"@HEAD:manifests/configmap.yaml"], 1)
+	require.Len(t, yamlFileNodes["git::"+dir+"@HEAD:other/secret.yaml"], 1)
+
+	// Check that only the YAML files of the requested directories are read from the revision
+	revisionFs := o.gitRevisions["git::"+dir+"@HEAD"].fs
+	for file, expected := range map[string]bool{
+		"manifests/configmap.yaml": true,
+		"other/secret.yaml":        true,
+		"manifests/NOTES.txt":      false,
+		"README.md":                false,
+	} {
+		exists, err := afero.Exists(revisionFs, path.Join(inputFsRoot, file))
+		require.Nil(t, err)
+		require.Equal(t, exists, expected, file)
+	}
+
+	// Check that missing revisions and directories are reported
+	o.inputs = []string{"git::" + dir + "@missing"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to read git input git::"+dir+"@missing: failed to resolve revision missing: reference not found")
+	o.inputs = []string{"git::" + dir + "@HEAD:missing"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to read git input git::"+dir+"@HEAD:missing: failed to find directory missing: directory not found")
+}
+
+func TestParseGitInput(t *testing.T) {
+	g, err := parseGitInput("git::path/to/repo@origin/main:manifests/")
+	require.Nil(t, err)
+	require.Equal(t, g, gitInput{repository: "path/to/repo", revision: "origin/main", dir: "manifests"})
+	require.Equal(t, g.name(), "git::path/to/repo@origin/main")
+
+	g, err = parseGitInput("git::.@v1.0.0")
+	require.Nil(t, err)
+	require.Equal(t, g, gitInput{repository: ".", revision: "v1.0.0"})
+
+	_, err = parseGitInput("git::path/to/repo")
+	require.Equal(t, err.Error(), "invalid git input git::path/to/repo, expected git::<repo-path>@<ref>[:<subdir>]")
+	_, err = parseGitInput("git::path/to/repo@:manifests")
+	require.Equal(t, err.Error(), "invalid git input git::path/to/repo@:manifests, revision not specified")
+}
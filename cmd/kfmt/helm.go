@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// Release name used if not specified, matching helm template
+	defaultReleaseName = "release-name"
+	// File in chart directories listing patterns of paths to leave out of the chart
+	helmIgnoreFileName = ".helmignore"
+	// Rendered template containing usage notes rather than manifests
+	helmNotesFileName = "NOTES.txt"
+)
+
+// renderHelmChart renders the templates of the Helm chart with the specified values, release name
+// and Namespace. The paths of the rendered templates and of the chart CRDs, which are only used for
+// discovery, are returned. Their nodes are read from the rendered files
+func (o *options) renderHelmChart(fs afero.Fs) ([]string, []string, error) {
+	if o.helmChart == "" {
+		return nil, nil, nil
+	}
+
+	c, err := loadHelmChart(fs, o.helmChart)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load Helm chart %s", o.helmChart)
+	}
+
+	values := map[string]interface{}{}
+	for _, valuesFile := range o.valuesFiles {
+		b, err := afero.ReadFile(fs, valuesFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		newValues, err := chartutil.ReadValues(b)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse values file %s", valuesFile)
+		}
+		// Later values files take precedence
+		values = chartutil.CoalesceTables(newValues, values)
+	}
+
+	err = chartutil.ProcessDependencies(c, values)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to process dependencies of Helm chart %s", o.helmChart)
+	}
+	releaseName := o.releaseName
+	if releaseName == "" {
+		releaseName = defaultReleaseName
+	}
+	renderValues, err := chartutil.ToRenderValues(c, values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: o.namespace,
+		IsInstall: true,
+	}, nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to compute values of Helm chart %s", o.helmChart)
+	}
+	templates, err := engine.Render(c, renderValues)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to render Helm chart %s", o.helmChart)
+	}
+
+	var templateFiles []string
+	for name, manifests := range templates {
+		if path.Base(name) == helmNotesFileName || strings.TrimSpace(manifests) == "" {
+			continue
+		}
+		templateFile := o.helmChartFile(c, name)
+		err = o.addRenderedFile(templateFile, []byte(manifests))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse rendered template %s", templateFile)
+		}
+		templateFiles = append(templateFiles, templateFile)
+	}
+	sort.Strings(templateFiles)
+
+	var crdFiles []string
+	for _, crd := range c.CRDObjects() {
+		crdFile := o.helmChartFile(c, crd.Filename)
+		err = o.addRenderedFile(crdFile, crd.File.Data)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse CRD %s", crdFile)
+		}
+		crdFiles = append(crdFiles, crdFile)
+	}
+
+	return templateFiles, crdFiles, nil
+}
+
+// helmChartFile converts the path of a file within a chart, which is prefixed with the chart name, to
+// its path within the chart directory
+func (o *options) helmChartFile(c *chart.Chart, name string) string {
+	return filepath.Join(o.helmChart, filepath.FromSlash(strings.TrimPrefix(name, c.Name()+"/")))
+}
+
+// addRenderedFile records the nodes of a file that is rendered rather than read
+func (o *options) addRenderedFile(file string, b []byte) error {
+	nodes, err := kio.FromBytes(b)
+	if err != nil {
+		return err
+	}
+	if o.renderedFiles == nil {
+		o.renderedFiles = map[string][]*yaml.RNode{}
+	}
+	o.renderedFiles[file] = nodes
+	return nil
+}
+
+// isRendered returns true if the nodes of file were rendered rather than read
+func (o *options) isRendered(file string) bool {
+	_, ok := o.renderedFiles[file]
+	return ok
+}
+
+// loadHelmChart loads the chart in chartDir, leaving out the paths matched by its .helmignore file
+func loadHelmChart(fs afero.Fs, chartDir string) (*chart.Chart, error) {
+	var patterns []ignorePattern
+	b, err := afero.ReadFile(fs, filepath.Join(chartDir, helmIgnoreFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		patterns, err = parseIgnoreFile(b, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", helmIgnoreFileName)
+		}
+	}
+
+	var files []*loader.BufferedFile
+	err = afero.Walk(fs, chartDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(chartDir, file)
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		if relative == "." {
+			return nil
+		}
+
+		if isIgnored(patterns, relative, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := afero.ReadFile(fs, file)
+		if err != nil {
+			return err
+		}
+		files = append(files, &loader.BufferedFile{Name: relative, Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadFiles(files)
+}
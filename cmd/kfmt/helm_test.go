@@ -0,0 +1,136 @@
+package main
+
+import (
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmChart(t *testing.T) {
+	// Setup options
+	o := &options{
+		helmChart:   "chart",
+		valuesFiles: []string{"values.yaml"},
+		releaseName: "test",
+		namespace:   "test",
+		output:      outputDirectory,
+		comment:     true,
+		remove:      true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create a chart using a CRD from its crds directory
+	for _, dir := range []string{"chart", "chart/crds", "chart/templates"} {
+		err := fs.Mkdir(dir, 0755)
+		require.Nil(t, err)
+	}
+	files := map[string]string{
+		"chart/Chart.yaml": `
+apiVersion: v2
+name: example
+version: 0.1.0
+`,
+		"chart/values.yaml": `
+data: default
+replicas: 1
+`,
+		"chart/.helmignore": "*.bak\n",
+		"chart/templates/_helpers.tpl": `
+{{- define "example.name" -}}
+{{ .Release.Name }}-example
+{{- end -}}
+`,
+		"chart/templates/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "example.name" . }}
+data:
+  data: {{ .Values.data }}
+  namespace: {{ .Release.Namespace }}
+`,
+		"chart/templates/tester.yaml": `
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: {{ include "example.name" . }}
+spec:
+  replicas: {{ .Values.replicas }}
+`,
+		"chart/templates/configmap.yaml.bak": "invalid {{\n",
+		"chart/templates/NOTES.txt":          "Installed {{ .Release.Name }}\n",
+		"chart/crds/tester.yaml": `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+spec:
+  group: test.io
+  names:
+    kind: Tester
+    plural: testers
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`,
+		"values.yaml": `
+data: override
+`,
+	}
+	for file, contents := range files {
+		err := afero.WriteFile(fs, file, []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+
+	// Check that templates are rendered and attributed to their template path and that chart CRDs are
+	// used for discovery but not written
+	err := o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-test-example.yaml"), `---
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-example
+  namespace: test
+data:
+  data: override
+  namespace: test
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/tester.test.io-test-example.yaml"), `---
+# Source: chart/templates/tester.yaml
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: test-example
+  namespace: test
+spec:
+  replicas: 1
+`)
+	require.Nil(t, err)
+	err = requireFileIsNotExist(fs, path.Join(outputDirectory, nonNamespacedDirectory, "customresourcedefinitions/testers.test.io.yaml"))
+	require.Nil(t, err)
+
+	// Check that chart files are not removed
+	for file, contents := range files {
+		err = requireRegularFileContents(fs, file, contents)
+		require.Nil(t, err)
+	}
+
+	// Check that template errors are reported
+	o = &options{
+		helmChart: "chart",
+		output:    outputDirectory,
+	}
+	err = afero.WriteFile(fs, "chart/templates/invalid.yaml", []byte("{{ .Values.missing.field }}\n"), 0644)
+	require.Nil(t, err)
+	err = o.run(fs)
+	require.Contains(t, err.Error(), "failed to render Helm chart chart: template: example/templates/invalid.yaml")
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dippynark/kfmt/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// File in input directories listing gitignore-style patterns of paths to ignore
+	ignoreFileName = ".kfmtignore"
+	// Pattern matching any number of directories
+	doubleStar = "**"
+)
+
+// ignorePattern is a gitignore-style pattern read from an ignore file
+type ignorePattern struct {
+	// Slash-separated path of the directory containing the ignore file relative to the input
+	// directory. The pattern only applies to paths within this directory
+	dir string
+	// Pattern matched against paths relative to dir
+	pattern string
+	// Re-include paths matched by earlier patterns
+	negate bool
+	// Only match directories
+	dirOnly bool
+}
+
+// parseIgnoreFile parses the gitignore-style patterns in an ignore file. Blank lines and lines
+// starting with # are ignored
+func parseIgnoreFile(b []byte, dir string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := ignorePattern{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		// Escaped leading characters are matched literally
+		line = strings.TrimPrefix(line, "\\")
+		if line == "" {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return patterns, errors.Wrapf(err, "invalid pattern %s", line)
+		}
+		pattern.pattern = line
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, scanner.Err()
+}
+
+// matchGlob returns true if the slash-separated path matches pattern. Patterns containing a slash
+// other than a trailing slash are matched against the whole path, otherwise they are matched against
+// the final element. ** matches any number of directories
+func matchGlob(pattern, name string) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+	if patterns[0] == doubleStar {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(patterns[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(patterns[0], segments[0])
+	return ok && matchSegments(patterns[1:], segments[1:])
+}
+
+// isIgnored returns true if the slash-separated path relative to the input directory is ignored by
+// patterns. As with gitignore, the last matching pattern wins
+func isIgnored(patterns []ignorePattern, name string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		relative := name
+		if pattern.dir != "" {
+			if !strings.HasPrefix(name, pattern.dir+"/") {
+				continue
+			}
+			relative = strings.TrimPrefix(name, pattern.dir+"/")
+		}
+		if matchGlob(pattern.pattern, relative) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+// isExcluded returns true if the slash-separated path relative to the input directory matches the
+// --exclude patterns or, for files, does not match the --include patterns
+func (o *options) isExcluded(name string, isDir bool) bool {
+	for _, exclude := range o.excludes {
+		if matchGlob(exclude, name) {
+			return true
+		}
+	}
+	if isDir || len(o.includes) == 0 {
+		return false
+	}
+	for _, include := range o.includes {
+		if matchGlob(include, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateGlobs returns an error if any --include or --exclude pattern is malformed
+func (o *options) validateGlobs() error {
+	for _, pattern := range append(append([]string{}, o.includes...), o.excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+	}
+	return nil
+}
+
+// listYAMLFiles lists YAML files to be processed. Paths matching the --include and --exclude patterns
+// and the ignore files found in inputDir are honoured
+func (o *options) listYAMLFiles(fs afero.Fs, inputDir string) ([]string, error) {
+	return walkYAMLFiles(fs, inputDir, o.isExcluded)
+}
+
+// walkYAMLFiles lists the YAML files in inputDir that are not ignored by the ignore files found in
+// inputDir or, if isExcluded is not nil, excluded by isExcluded
+func walkYAMLFiles(fs afero.Fs, inputDir string, isExcluded func(name string, isDir bool) bool) ([]string, error) {
+	if isExcluded == nil {
+		isExcluded = func(string, bool) bool { return false }
+	}
+
+	var files []string
+	var patterns []ignorePattern
+
+	err := afero.Walk(fs, inputDir,
+		func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relative, err := filepath.Rel(inputDir, file)
+			if err != nil {
+				return err
+			}
+			relative = filepath.ToSlash(relative)
+
+			if info.IsDir() {
+				if relative != "." && (isExcluded(relative, true) || isIgnored(patterns, relative, true)) {
+					return filepath.SkipDir
+				}
+
+				// Patterns in ignore files apply to the directory containing them. Directories are
+				// walked in lexical order so patterns are read before the paths they apply to
+				b, err := afero.ReadFile(fs, filepath.Join(file, ignoreFileName))
+				if os.IsNotExist(err) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				dir := relative
+				if dir == "." {
+					dir = ""
+				}
+				newPatterns, err := parseIgnoreFile(b, dir)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse %s", filepath.Join(file, ignoreFileName))
+				}
+				patterns = append(patterns, newPatterns...)
+				return nil
+			}
+
+			// Assume regular file is valid YAML or JSON file if it has an appropriate extension
+			if info.Mode().IsRegular() && isYAMLFile(file) {
+				if isExcluded(relative, false) || isIgnored(patterns, relative, false) {
+					return nil
+				}
+				// Directories often contain JSON files that are not manifests, such as package.json
+				// or discovery snapshots, so only JSON files containing manifests are listed
+				if strings.HasSuffix(file, ".json") {
+					b, err := afero.ReadFile(fs, file)
+					if err != nil {
+						return err
+					}
+					if !utils.IsJSONManifests(b) {
+						return nil
+					}
+				}
+				files = append(files, file)
+			}
+			return nil
+		})
+	if err != nil {
+		return files, err
+	}
+
+	return files, err
+}
+
+// isYAMLFile returns true if file has a YAML or JSON extension
+func isYAMLFile(file string) bool {
+	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml") || strings.HasSuffix(file, ".json")
+}
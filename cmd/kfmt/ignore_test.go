@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnore(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:   []string{"input"},
+		output:   outputDirectory,
+		excludes: []string{".github"},
+		remove:   true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input directory containing files that are not manifests
+	for _, dir := range []string{"input", "input/.github", "input/chart", "input/chart/templates", "input/test", "input/test/fixtures"} {
+		err := fs.Mkdir(dir, 0755)
+		require.Nil(t, err)
+	}
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+`
+	files := map[string]string{
+		"input/.kfmtignore":                    "# Helm charts and test fixtures\nvalues.yaml\ntest/\n",
+		"input/.github/workflow.yaml":          "on: push\n",
+		"input/chart/.kfmtignore":              "/templates/*\n!/templates/configmap.yaml\n",
+		"input/chart/values.yaml":              "replicas: 1\n",
+		"input/chart/templates/secret.yaml":    "{{ .Values.secret }}\n",
+		"input/chart/templates/configmap.yaml": fmt.Sprintf(manifest, "chart"),
+		"input/test/fixtures/invalid.yaml":     "invalid\n",
+		"input/configmap.yaml":                 fmt.Sprintf(manifest, "test"),
+	}
+	for file, contents := range files {
+		err := afero.WriteFile(fs, file, []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+
+	// Check that ignored and excluded files are neither processed nor removed
+	err := o.run(fs)
+	require.Nil(t, err)
+	for _, name := range []string{"chart", "test"} {
+		err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-"+name+".yaml"), fmt.Sprintf(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: default
+`, name))
+		require.Nil(t, err)
+	}
+	for file, contents := range files {
+		if file == "input/configmap.yaml" || file == "input/chart/templates/configmap.yaml" {
+			err = requireFileIsNotExist(fs, file)
+		} else {
+			err = requireRegularFileContents(fs, file, contents)
+		}
+		require.Nil(t, err)
+	}
+
+	// Check that only included files are processed
+	o = &options{
+		inputs:   []string{"input"},
+		output:   outputDirectory,
+		includes: []string{"chart/**"},
+	}
+	err = afero.WriteFile(fs, "input/chart/templates/configmap.yaml", []byte(fmt.Sprintf(manifest, "chart")), 0644)
+	require.Nil(t, err)
+	yamlFiles, err := o.findYAMLFiles(fs)
+	require.Nil(t, err)
+	require.Equal(t, yamlFiles, []string{"input/chart/templates/configmap.yaml"})
+
+	// Check that the include and exclude patterns do not apply to CRD directories but ignore files do
+	for _, dir := range []string{"crds", "crds/ignored"} {
+		err = fs.Mkdir(dir, 0755)
+		require.Nil(t, err)
+	}
+	for file, contents := range map[string]string{
+		"crds/.kfmtignore":       "ignored/\n",
+		"crds/tester.yaml":       "kind: CustomResourceDefinition\n",
+		"crds/ignored/test.yaml": "kind: CustomResourceDefinition\n",
+	} {
+		err = afero.WriteFile(fs, file, []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+	o.crdDirs = []string{"crds"}
+	o.excludes = []string{"tester.yaml"}
+	crdFiles, err := o.findCRDFiles(fs)
+	require.Nil(t, err)
+	require.Equal(t, crdFiles, []string{"crds/tester.yaml"})
+
+	// Check that malformed patterns are rejected
+	o.excludes = []string{"["}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "invalid pattern [: syntax error in pattern")
+}
+
+func TestMatchGlob(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"values.yaml", "chart/values.yaml", true},
+		{"*.yaml", "a/b/c.yaml", true},
+		{"chart/*.yaml", "chart/values.yaml", true},
+		{"chart/*.yaml", "other/chart/values.yaml", false},
+		{"/chart", "chart", true},
+		{"**/values.yaml", "values.yaml", true},
+		{"**/values.yaml", "a/b/values.yaml", true},
+		{"chart/**", "chart/templates/secret.yaml", true},
+		{"chart/**", "other/chart", false},
+		{"a/**/b.yaml", "a/x/y/b.yaml", true},
+	} {
+		require.Equal(t, matchGlob(test.pattern, test.name), test.match, "%s %s", test.pattern, test.name)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// Separates the name of an input filesystem from the path of a file within it (e.g.
+	// bundle.tgz:path/inside.yaml)
+	inputFsSeparator = ":"
+	// Root directory of input filesystems
+	inputFsRoot = "/"
+)
+
+// addInputFs records an input filesystem, such as the contents of an archive, and lists the YAML
+// files in dir within it. Files are named using the name of the filesystem and their path within it
+func (o *options) addInputFs(name string, inputFs afero.Fs, dir string) ([]string, error) {
+	if o.inputFss == nil {
+		o.inputFss = map[string]afero.Fs{}
+	}
+	o.inputFss[name] = inputFs
+
+	entries, err := o.listYAMLFiles(inputFs, path.Join(inputFsRoot, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		files = append(files, name+inputFsSeparator+strings.TrimPrefix(filepath.ToSlash(entry), inputFsRoot))
+	}
+	return files, nil
+}
+
+// findInputFs returns the name of the input filesystem containing file and the path of file within
+// it. If file is not contained in an input filesystem then ok is false
+func (o *options) findInputFs(file string) (name string, entry string, ok bool) {
+	for name := range o.inputFss {
+		if strings.HasPrefix(file, name+inputFsSeparator) {
+			return name, strings.TrimPrefix(file, name+inputFsSeparator), true
+		}
+	}
+	return "", "", false
+}
+
+// readInputFile reads an input file, which may be contained in an input filesystem or downloaded
+func (o *options) readInputFile(fs afero.Fs, file string) ([]byte, error) {
+	if b, ok := o.downloadedFiles[file]; ok {
+		return b, nil
+	}
+	if name, entry, ok := o.findInputFs(file); ok {
+		return afero.ReadFile(o.inputFss[name], inputFsRoot+entry)
+	}
+	return afero.ReadFile(fs, file)
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// annotationKustomizeSourceKey records the resource file that a rendered resource was read from
+const annotationKustomizeSourceKey = "kfmt.dev/kustomize-source"
+
+// findKustomizationFile returns the kustomization file in dir. If dir does not contain a
+// kustomization file then ok is false
+func findKustomizationFile(fs afero.Fs, dir string) (kustomizationFile string, ok bool) {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		kustomizationFile := filepath.Join(dir, name)
+		if info, err := fs.Stat(kustomizationFile); err == nil && info.Mode().IsRegular() {
+			return kustomizationFile, true
+		}
+	}
+	return "", false
+}
+
+// findKustomizations renders the kustomization directories, returning the files the rendered
+// resources are attributed to
+func (o *options) findKustomizations(fs afero.Fs) ([]string, error) {
+	var renderedFiles []string
+	for _, kustomization := range o.kustomizations {
+		kustomizationFile, ok := findKustomizationFile(fs, kustomization)
+		if !ok {
+			return renderedFiles, errors.Errorf("failed to find kustomization file in %s", kustomization)
+		}
+		files, err := o.addKustomization(fs, kustomizationFile)
+		if err != nil {
+			return renderedFiles, err
+		}
+		renderedFiles = append(renderedFiles, files...)
+	}
+	return renderedFiles, nil
+}
+
+// addKustomization renders the kustomization directory containing kustomizationFile and records the
+// rendered resources against the resource file they were read from. Resources that are not read
+// from a resource file, such as those created by generators, are attributed to kustomizationFile.
+// The files the rendered resources are attributed to are returned in the order they are rendered
+func (o *options) addKustomization(fs afero.Fs, kustomizationFile string) ([]string, error) {
+	nodes, err := renderKustomization(fs, kustomizationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.renderedFiles == nil {
+		o.renderedFiles = map[string][]*yaml.RNode{}
+	}
+	var files []string
+	seen := map[string]struct{}{}
+	for _, node := range nodes {
+		file, err := node.Pipe(yaml.GetAnnotation(annotationKustomizeSourceKey))
+		if err != nil {
+			return nil, err
+		}
+		source := kustomizationFile
+		if file != nil {
+			source = yaml.GetValue(file)
+			err = node.PipeE(yaml.ClearAnnotation(annotationKustomizeSourceKey))
+			if err != nil {
+				return nil, err
+			}
+			err = yaml.ClearEmptyAnnotations(node)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Resource files may be shared by several kustomizations
+		if _, ok := seen[source]; !ok {
+			seen[source] = struct{}{}
+			if _, ok := o.renderedFiles[source]; !ok {
+				files = append(files, source)
+			}
+		}
+		o.renderedFiles[source] = append(o.renderedFiles[source], node)
+	}
+	return files, nil
+}
+
+// renderKustomization renders the kustomization directory containing kustomizationFile. Rendered
+// resources are annotated with the resource file they were read from
+func renderKustomization(fs afero.Fs, kustomizationFile string) ([]*yaml.RNode, error) {
+	fileSystem, err := newKustomizeFileSystem(fs)
+	if err != nil {
+		return nil, err
+	}
+	err = fileSystem.findResourceFiles(filepath.Dir(kustomizationFile), map[string]struct{}{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find resources of kustomization %s", kustomizationFile)
+	}
+
+	options := krusty.MakeDefaultOptions()
+	options.UseKyaml = true
+	resMap, err := krusty.MakeKustomizer(fileSystem, options).Run(filepath.Dir(kustomizationFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render kustomization %s", kustomizationFile)
+	}
+	b, err := resMap.AsYaml()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render kustomization %s", kustomizationFile)
+	}
+	nodes, err := kio.FromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	// Kustomize sorts fields alphabetically so fields are restored to the conventional order
+	return filters.FormatFilter{}.Filter(nodes)
+}
+
+// findResourceFiles records the resource files of the kustomization in dir and of the kustomizations
+// and components it includes. Remote resources and resources that do not exist are left for kustomize
+// to load or report
+func (k kustomizeFileSystem) findResourceFiles(dir string, visited map[string]struct{}) error {
+	dir = k.rel(k.abs(dir))
+	if _, ok := visited[dir]; ok {
+		return nil
+	}
+	visited[dir] = struct{}{}
+
+	kustomizationFile, ok := findKustomizationFile(k.fs, dir)
+	if !ok {
+		return nil
+	}
+	b, err := afero.ReadFile(k.fs, kustomizationFile)
+	if err != nil {
+		return err
+	}
+	var kustomization types.Kustomization
+	err = sigsyaml.Unmarshal(b, &kustomization)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s", kustomizationFile)
+	}
+	kustomization.FixKustomizationPostUnmarshalling()
+
+	for _, resource := range append(kustomization.Resources, kustomization.Components...) {
+		path := filepath.Join(dir, resource)
+		info, err := k.fs.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			err = k.findResourceFiles(path, visited)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		k.resourceFiles[k.rel(k.abs(path))] = struct{}{}
+	}
+	return nil
+}
+
+// annotateResourceFile annotates each resource in the contents of a resource file with the file.
+// Contents that cannot be parsed are returned unchanged for kustomize to report
+func annotateResourceFile(file string, b []byte) []byte {
+	nodes, err := kio.FromBytes(b)
+	if err != nil {
+		return b
+	}
+	for _, node := range nodes {
+		err = node.PipeE(yaml.SetAnnotation(annotationKustomizeSourceKey, file))
+		if err != nil {
+			return b
+		}
+	}
+	s, err := kio.StringAll(nodes)
+	if err != nil {
+		return b
+	}
+	return []byte(s)
+}
+
+// kustomizeFileSystem adapts an afero filesystem to the filesystem used by kustomize. Kustomize
+// requires absolute paths, which are resolved against the working directory and made relative again
+// before being passed to the afero filesystem so that relative input paths continue to work
+type kustomizeFileSystem struct {
+	fs         afero.Fs
+	workingDir string
+	// resourceFiles contains the resource files whose resources are annotated with their file
+	resourceFiles map[string]struct{}
+}
+
+var _ filesys.FileSystem = kustomizeFileSystem{}
+
+func newKustomizeFileSystem(fs afero.Fs) (kustomizeFileSystem, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return kustomizeFileSystem{}, errors.Wrap(err, "failed to get working directory")
+	}
+	return kustomizeFileSystem{fs: fs, workingDir: workingDir, resourceFiles: map[string]struct{}{}}, nil
+}
+
+// abs returns the absolute path of path
+func (k kustomizeFileSystem) abs(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(k.workingDir, path)
+}
+
+// rel returns the path passed to the afero filesystem
+func (k kustomizeFileSystem) rel(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(k.workingDir, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+func (k kustomizeFileSystem) Create(path string) (filesys.File, error) {
+	return k.fs.Create(k.rel(path))
+}
+
+func (k kustomizeFileSystem) Mkdir(path string) error {
+	return k.fs.Mkdir(k.rel(path), defaultDirectoryPerms)
+}
+
+func (k kustomizeFileSystem) MkdirAll(path string) error {
+	return k.fs.MkdirAll(k.rel(path), defaultDirectoryPerms)
+}
+
+func (k kustomizeFileSystem) RemoveAll(path string) error {
+	return k.fs.RemoveAll(k.rel(path))
+}
+
+func (k kustomizeFileSystem) Open(path string) (filesys.File, error) {
+	return k.fs.Open(k.rel(path))
+}
+
+func (k kustomizeFileSystem) IsDir(path string) bool {
+	isDir, err := afero.IsDir(k.fs, k.rel(path))
+	return err == nil && isDir
+}
+
+func (k kustomizeFileSystem) CleanedAbs(path string) (filesys.ConfirmedDir, string, error) {
+	abs := k.abs(path)
+	if k.IsDir(abs) {
+		return filesys.ConfirmedDir(abs), "", nil
+	}
+	dir := filepath.Dir(abs)
+	if !k.IsDir(dir) {
+		return "", "", errors.Errorf("first part of %s is not a directory", path)
+	}
+	return filesys.ConfirmedDir(dir), filepath.Base(abs), nil
+}
+
+func (k kustomizeFileSystem) Exists(path string) bool {
+	exists, err := afero.Exists(k.fs, k.rel(path))
+	return err == nil && exists
+}
+
+func (k kustomizeFileSystem) Glob(pattern string) ([]string, error) {
+	matches, err := afero.Glob(k.fs, k.rel(pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		matches[i] = k.abs(matches[i])
+	}
+	return matches, nil
+}
+
+func (k kustomizeFileSystem) ReadFile(path string) ([]byte, error) {
+	b, err := afero.ReadFile(k.fs, k.rel(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := k.resourceFiles[k.rel(k.abs(path))]; ok {
+		return annotateResourceFile(k.rel(k.abs(path)), b), nil
+	}
+	return b, nil
+}
+
+func (k kustomizeFileSystem) WriteFile(path string, data []byte) error {
+	return afero.WriteFile(k.fs, k.rel(path), data, defaultFilePerms)
+}
+
+func (k kustomizeFileSystem) Walk(path string, walkFn filepath.WalkFunc) error {
+	return afero.Walk(k.fs, k.rel(path), func(path string, info os.FileInfo, err error) error {
+		return walkFn(k.abs(path), info, err)
+	})
+}
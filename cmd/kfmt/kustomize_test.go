@@ -0,0 +1,144 @@
+package main
+
+import (
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKustomize(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:  []string{"overlay"},
+		output:  outputDirectory,
+		comment: true,
+		remove:  true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create a base and an overlay
+	for _, dir := range []string{"base", "overlay"} {
+		err := fs.Mkdir(dir, 0755)
+		require.Nil(t, err)
+	}
+	files := map[string]string{
+		"base/kustomization.yaml": `
+resources:
+- configmap.yaml
+`,
+		"base/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  key: value
+`,
+		"overlay/kustomization.yaml": `
+namespace: test
+namePrefix: overlay-
+resources:
+- ../base
+- namespace.yaml
+patchesStrategicMerge:
+- patch.yaml
+configMapGenerator:
+- name: generated
+  literals:
+  - key=value
+generatorOptions:
+  disableNameSuffixHash: true
+`,
+		"overlay/patch.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  patched: "true"
+`,
+		"overlay/namespace.yaml": `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`,
+	}
+	for file, contents := range files {
+		err := afero.WriteFile(fs, file, []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+
+	// Check that the overlay is rendered, that resources are attributed to the file they were read
+	// from and that its kustomization files are not removed
+	err := o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-overlay-test.yaml"), `---
+# Source: base/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overlay-test
+  namespace: test
+data:
+  key: value
+  patched: "true"
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-overlay-generated.yaml"), `---
+# Source: overlay/kustomization.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overlay-generated
+  namespace: test
+data:
+  key: value
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "namespaces/test.yaml"), `---
+# Source: overlay/namespace.yaml
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`)
+	require.Nil(t, err)
+	for file, contents := range files {
+		err = requireRegularFileContents(fs, file, contents)
+		require.Nil(t, err)
+	}
+
+	// Check that kustomization directories can be specified explicitly
+	o = &options{
+		kustomizations: []string{"base"},
+		output:         outputDirectory,
+		overwrite:      true,
+	}
+	yamlFiles, err := o.findYAMLFiles(fs)
+	require.Nil(t, err)
+	require.Equal(t, yamlFiles, []string{"base/configmap.yaml"})
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-test.yaml"), `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+data:
+  key: value
+`)
+	require.Nil(t, err)
+
+	// Check that directories without a kustomization file are rejected
+	o.kustomizations = []string{"missing"}
+	err = fs.Mkdir("missing", 0755)
+	require.Nil(t, err)
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to find kustomization file in missing")
+}
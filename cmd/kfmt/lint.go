@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dippynark/kfmt/pkg/discovery"
+	"github.com/dippynark/kfmt/pkg/utils"
+	"github.com/pkg/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// lintNodes reports each manifest using an API that is deprecated or removed in the target Kubernetes
+// version and fails if any removed APIs are found
+func (o *options) lintNodes(yamlFileNodes map[string][]*yaml.RNode, w io.Writer) error {
+	kubernetesVersion, err := utilversion.ParseGeneric(o.kubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse Kubernetes version")
+	}
+
+	// Sort input files so that findings are reported consistently
+	var yamlFiles []string
+	for yamlFile := range yamlFileNodes {
+		yamlFiles = append(yamlFiles, yamlFile)
+	}
+	sort.Strings(yamlFiles)
+
+	removed := 0
+	for _, yamlFile := range yamlFiles {
+		for _, node := range yamlFileNodes[yamlFile] {
+			gvk, err := utils.GetGVK(node)
+			if err != nil {
+				return err
+			}
+
+			lifecycle, ok := discovery.GetAPILifecycle(gvk)
+			if !ok {
+				continue
+			}
+
+			var finding string
+			switch {
+			case lifecycle.RemovedIn(kubernetesVersion):
+				finding = fmt.Sprintf("was removed in Kubernetes %s", lifecycle.Removed)
+				removed++
+			case lifecycle.DeprecatedIn(kubernetesVersion):
+				finding = fmt.Sprintf("is deprecated in Kubernetes %s", lifecycle.Deprecated)
+				if lifecycle.Removed != "" {
+					finding = fmt.Sprintf("%s and will be removed in %s", finding, lifecycle.Removed)
+				}
+			default:
+				continue
+			}
+
+			name, err := utils.GetName(node)
+			if err != nil {
+				return err
+			}
+			namespace, err := utils.GetNamespace(node)
+			if err != nil {
+				return err
+			}
+			if namespace != "" {
+				name = namespace + "/" + name
+			}
+
+			replacement := ""
+			if !lifecycle.Replacement.Empty() {
+				replacement = fmt.Sprintf(", use %s %s instead", lifecycle.Replacement.GroupVersion().String(), lifecycle.Replacement.Kind)
+			}
+
+			fmt.Fprintf(w, "%s: %s %s uses %s which %s%s\n", yamlFile, gvk.Kind, name, gvk.GroupVersion().String(), finding, replacement)
+		}
+	}
+
+	if removed > 0 {
+		return errors.Errorf("found %s using APIs removed in Kubernetes %s", countNoun(removed, "manifest"), o.kubernetesVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:            []string{"input.yaml"},
+		lint:              true,
+		kubernetesVersion: "1.24",
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using deprecated and removed APIs
+	manifests := `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: example
+  namespace: test
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: example
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Check that removed APIs fail the run
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "found 1 manifest using APIs removed in Kubernetes 1.24")
+
+	// Check that deprecated and removed APIs are reported
+	yamlFileNodes, err := o.findYAMLFileNodes(fs, []string{"input.yaml"})
+	require.Nil(t, err)
+	var b bytes.Buffer
+	err = o.lintNodes(yamlFileNodes, &b)
+	require.NotNil(t, err)
+	require.Equal(t, b.String(), `input.yaml: Ingress test/example uses extensions/v1beta1 which was removed in Kubernetes 1.22, use networking.k8s.io/v1 Ingress instead
+input.yaml: CronJob example uses batch/v1beta1 which is deprecated in Kubernetes 1.21 and will be removed in 1.25, use batch/v1 CronJob instead
+`)
+
+	// Check that each manifest using a removed API is counted
+	o.kubernetesVersion = "1.25"
+	b.Reset()
+	err = o.lintNodes(yamlFileNodes, &b)
+	require.Equal(t, err.Error(), "found 2 manifests using APIs removed in Kubernetes 1.25")
+
+	// Ensure deprecated APIs do not fail the run
+	o.kubernetesVersion = "1.21"
+	b.Reset()
+	err = o.lintNodes(yamlFileNodes, &b)
+	require.Nil(t, err)
+	require.Equal(t, b.String(), `input.yaml: Ingress test/example uses extensions/v1beta1 which is deprecated in Kubernetes 1.14 and will be removed in 1.22, use networking.k8s.io/v1 Ingress instead
+input.yaml: CronJob example uses batch/v1beta1 which is deprecated in Kubernetes 1.21 and will be removed in 1.25, use batch/v1 CronJob instead
+`)
+
+	// Check that replacements are reported using the replacement kind
+	err = afero.WriteFile(fs, "ingressclass.yaml", []byte(`
+apiVersion: networking.k8s.io/v1beta1
+kind: IngressClass
+metadata:
+  name: example
+`), 0644)
+	require.Nil(t, err)
+	yamlFileNodes, err = o.findYAMLFileNodes(fs, []string{"ingressclass.yaml"})
+	require.Nil(t, err)
+	b.Reset()
+	err = o.lintNodes(yamlFileNodes, &b)
+	require.Nil(t, err)
+	require.Equal(t, b.String(), `ingressclass.yaml: IngressClass example uses networking.k8s.io/v1beta1 which is deprecated in Kubernetes 1.19 and will be removed in 1.22, use networking.k8s.io/v1 IngressClass instead
+`)
+
+	// Ensure a Kubernetes version is required
+	o.kubernetesVersion = ""
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "Kubernetes version must be specified to lint manifests")
+}
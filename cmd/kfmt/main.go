@@ -3,13 +3,15 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/dippynark/kfmt/pkg/discovery"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
-	"k8s.io/client-go/util/homedir"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Set to `git describe --tags`
@@ -20,7 +22,12 @@ const (
 	annotationNamespacesKey = "kfmt.dev/namespaces"
 	annotationNamespacesAll = "*"
 
-	kubeconfigEnvVar = "KUBECONFIG"
+	defaultDiscoveryCacheTTL = 6 * time.Hour
+	defaultDownloadTimeout   = 30 * time.Second
+
+	// Cached discovery files read from the working directory if no others are specified
+	defaultAPIResourcesFile = "api-resources.txt"
+	defaultAPIVersionsFile  = "api-versions.txt"
 
 	manifestSeparator = "---\n"
 
@@ -45,11 +52,20 @@ func main() {
 
 	cmd.Flags().BoolP("help", "h", false, "Print help text")
 	cmd.Flags().BoolVarP(&o.version, "version", "v", false, "Print version")
-	cmd.Flags().StringArrayVarP(&o.inputs, "input", "i", []string{}, fmt.Sprintf("Input files or directories containing manifests. If no input is specified %s will be used", os.Stdin.Name()))
+	cmd.Flags().StringArrayVarP(&o.inputs, "input", "i", []string{}, fmt.Sprintf("Input files, directories, archives (.tar, .tar.gz, .tgz or .zip) or directories at a git revision of a local repository (git::<repo-path>@<ref>[:<subdir>]) containing manifests. HTTP(S) URLs of manifest files may also be specified with an optional SHA-256 checksum (e.g. https://example.com/install.yaml#sha256=<checksum>). If no input is specified %s will be used", os.Stdin.Name()))
+	cmd.Flags().StringArrayVar(&o.kustomizations, "kustomize", []string{}, "Kustomization directories to render as input. Input directories containing a kustomization file are also rendered")
+	cmd.Flags().StringVar(&o.helmChart, "helm-chart", "", "Local Helm chart directory to render as input. CRDs in the crds directory of the chart are used for discovery")
+	cmd.Flags().StringArrayVar(&o.valuesFiles, "values", []string{}, "Values files used to render the Helm chart. Later files take precedence")
+	cmd.Flags().StringVar(&o.releaseName, "release-name", defaultReleaseName, "Release name used to render the Helm chart")
+	cmd.Flags().DurationVar(&o.downloadTimeout, "download-timeout", defaultDownloadTimeout, "Timeout for downloading each remote input")
+	cmd.Flags().StringVar(&o.downloadCacheDir, "download-cache-dir", "", "Directory used to cache remote inputs whose checksum is pinned between runs (e.g. ~/.cache/kfmt). If not specified remote inputs are not cached")
+	cmd.Flags().StringArrayVar(&o.includes, "include", []string{}, fmt.Sprintf("Glob pattern of paths relative to input directories to process (e.g. 'manifests/**'). Patterns without a slash match file names. If specified only matching files are processed. Paths ignored by %s files in input directories are not processed", ignoreFileName))
+	cmd.Flags().StringArrayVar(&o.excludes, "exclude", []string{}, "Glob pattern of paths relative to input directories not to process (e.g. '.github' or '**/values.yaml'). Patterns without a slash match file and directory names")
+	cmd.Flags().StringArrayVar(&o.crdDirs, "crd-dir", []string{}, "Directories containing CRDs used for discovery. Manifests in these directories are not written to the output directory")
 	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output directory to write organised manifests")
 	cmd.Flags().StringArrayVarP(&o.filters, "filter", "f", []string{}, "Filter Kind.group from output manifests (e.g. Deployment.apps or Secret)")
 	cmd.Flags().StringArrayVarP(&o.gvkScopes, "gvk-scope", "g", []string{}, "Add GVK scope mapping Kind.group/version:Cluster or Kind.group/version:Namespaced to discovery")
-	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", corev1.NamespaceDefault, "Set metadata.namespace field if missing from namespaced resources")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", corev1.NamespaceDefault, "Set metadata.namespace field if missing from namespaced resources. Also used as the release Namespace when rendering the Helm chart")
 	cmd.Flags().BoolVar(&o.clean, "clean", false, "Remove metadata.namespace field from non-namespaced resources")
 	cmd.Flags().BoolVar(&o.strict, "strict", false, "Require metadata.namespace field is not set for non-namespaced resources")
 	cmd.Flags().BoolVar(&o.remove, "remove", false, "Remove processed input files")
@@ -57,14 +73,25 @@ func main() {
 	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "Overwrite existing output files")
 	cmd.Flags().BoolVar(&o.createMissingNamespaces, "create-missing-namespaces", false, "Create missing Namespace manifests")
 	cmd.Flags().BoolVarP(&o.discovery, "discovery", "d", false, "Use API Server for discovery")
-	// https://github.com/kubernetes/client-go/blob/b72204b2445de5ac815ae2bb993f6182d271fdb4/examples/out-of-cluster-client-configuration/main.go#L45-L49
-	if kubeconfigEnvVarValue := os.Getenv(kubeconfigEnvVar); kubeconfigEnvVarValue != "" {
-		cmd.Flags().StringVarP(&o.kubeconfig, "kubeconfig", "k", kubeconfigEnvVarValue, "Path to the kubeconfig file used for discovery")
-	} else if home := homedir.HomeDir(); home != "" {
-		cmd.Flags().StringVarP(&o.kubeconfig, "kubeconfig", "k", filepath.Join(home, ".kube", "config"), "Path to the kubeconfig file used for discovery")
-	} else {
-		cmd.Flags().StringVarP(&o.kubeconfig, "kubeconfig", "k", "", "Path to the kubeconfig file used for discovery")
-	}
+	cmd.Flags().StringVar(&o.discoveryCacheDir, "discovery-cache-dir", "", "Directory used to cache API Server discovery information between runs (e.g. ~/.kube/cache/kfmt). If not specified discovery information is not cached")
+	cmd.Flags().DurationVar(&o.discoveryCacheTTL, "discovery-cache-ttl", defaultDiscoveryCacheTTL, "Duration for which cached API Server discovery information is used before being refreshed")
+	cmd.Flags().BoolVar(&o.refreshDiscovery, "refresh-discovery", false, "Refresh cached API Server discovery information")
+	cmd.Flags().StringSliceVar(&o.discoveryOrder, "discovery-order", discovery.DefaultSourceOrder, fmt.Sprintf("Order in which discovery sources are consulted. Omitted sources are not consulted. Valid sources are %s", strings.Join(discovery.DefaultSourceOrder, ", ")))
+	cmd.Flags().StringVar(&o.scopeConflictPolicy, "scope-conflict-policy", scopeConflictPolicyWarn, "Policy applied when discovery sources disagree about the scope of a GVK: error, warn or prefer-<source> (e.g. prefer-crds)")
+	cmd.Flags().StringVar(&o.unknownScopePolicy, "unknown-scope", unknownScopePolicyError, "Policy applied to resources whose scope cannot be determined: error, skip, cluster, namespaced or quarantine. Quarantined resources are written unchanged into the unknown directory of the output directory")
+	cmd.Flags().StringVar(&o.kubernetesVersion, "kubernetes-version", "", fmt.Sprintf("Kubernetes version whose core resources are used for discovery. Supported versions are %s", strings.Join(discovery.KubernetesVersions(), ", ")))
+	cmd.Flags().BoolVar(&o.lint, "lint", false, "Report manifests using APIs that are deprecated or removed in the Kubernetes version specified by --kubernetes-version instead of writing output. Fails if removed APIs are found")
+	cmd.Flags().BoolVar(&o.migrateAPIs, "migrate-apis", false, "Rewrite manifests using deprecated or removed APIs to use their replacements. If --kubernetes-version is specified only APIs deprecated or removed in that version are migrated")
+	cmd.Flags().BoolVar(&o.disableCatalog, "disable-catalog", false, "Disable the embedded discovery information for the CRDs of well-known projects (e.g. cert-manager, Flux and Argo CD)")
+	cmd.Flags().BoolVar(&o.strictDiscovery, "strict-discovery", false, "Require an exact GVK match during discovery rather than using the scope of other versions of the same kind")
+	cmd.Flags().StringArrayVar(&o.apiResources, "api-resources", []string{}, fmt.Sprintf("Path to cached kubectl api-resources output used for discovery. Later files take precedence. If not specified %s will be used if it exists", defaultAPIResourcesFile))
+	cmd.Flags().StringArrayVar(&o.apiVersions, "api-versions", []string{}, fmt.Sprintf("Path to cached kubectl api-versions output used for discovery. If not specified %s will be used if it exists", defaultAPIVersionsFile))
+	cmd.PersistentFlags().StringVarP(&o.kubeconfig, "kubeconfig", "k", "", fmt.Sprintf("Path to the kubeconfig file used for discovery. If not specified the files listed in the %s environment variable or ~/.kube/config are used, falling back to in-cluster configuration", clientcmd.RecommendedConfigPathEnvVar))
+	cmd.PersistentFlags().StringArrayVar(&o.contexts, "context", []string{}, "Name of the kubeconfig context used for discovery. Can be repeated to discover resources served by any of several clusters. If not specified the current context is used")
+	cmd.PersistentFlags().StringVar(&o.cluster, "cluster", "", "Name of the kubeconfig cluster used for discovery. Cannot be used with more than one context")
+	cmd.PersistentFlags().StringVar(&o.user, "user", "", "Name of the kubeconfig user used for discovery. Cannot be used with more than one context")
+
+	cmd.AddCommand(newDiscoveryCommand(o))
 
 	if err := cmd.Execute(); err != nil {
 		if err != nil {
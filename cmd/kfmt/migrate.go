@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dippynark/kfmt/pkg/migrate"
+	"github.com/pkg/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// migrateNodes rewrites nodes using deprecated or removed APIs to use their replacements. Nodes
+// that cannot be migrated automatically are reported and left unchanged
+func (o *options) migrateNodes(yamlFileNodes map[string][]*yaml.RNode, warnings io.Writer) error {
+	var kubernetesVersion *utilversion.Version
+	if o.kubernetesVersion != "" {
+		var err error
+		kubernetesVersion, err = utilversion.ParseGeneric(o.kubernetesVersion)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse Kubernetes version")
+		}
+	}
+
+	// Sort input files so that warnings are reported consistently
+	var yamlFiles []string
+	for yamlFile := range yamlFileNodes {
+		yamlFiles = append(yamlFiles, yamlFile)
+	}
+	sort.Strings(yamlFiles)
+
+	for _, yamlFile := range yamlFiles {
+		for _, node := range yamlFileNodes[yamlFile] {
+			_, err := migrate.Migrate(node, kubernetesVersion)
+			if _, ok := err.(*migrate.UnsupportedError); ok {
+				fmt.Fprintf(warnings, "warning: %s: %s\n", yamlFile, err)
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to migrate manifest in %s", yamlFile)
+			}
+		}
+	}
+
+	return nil
+}
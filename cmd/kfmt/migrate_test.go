@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestMigrateAPIs(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:      []string{"input.yaml"},
+		output:      outputDirectory,
+		migrateAPIs: true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using removed APIs
+	manifests := `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: example
+spec:
+  backend:
+    serviceName: example
+    servicePort: 80
+---
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  template:
+    metadata:
+      labels:
+        app: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Format input manifests
+	err = o.run(fs)
+	require.Nil(t, err)
+
+	// Ensure output manifests are migrated
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/ingress-example.yaml"), `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: example
+  namespace: default
+spec:
+  defaultBackend:
+    service:
+      name: example
+      port:
+        number: 80
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/deployment-example.yaml"), `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+  namespace: default
+spec:
+  template:
+    metadata:
+      labels:
+        app: example
+  selector:
+    matchLabels:
+      app: example
+  revisionHistoryLimit: 2147483647
+  progressDeadlineSeconds: 2147483647
+  strategy:
+    rollingUpdate:
+      maxUnavailable: 1
+      maxSurge: 1
+`)
+	require.Nil(t, err)
+
+	// Ensure manifests that cannot be migrated are reported in file order
+	yamlFileNodes := map[string][]*yaml.RNode{}
+	for _, yamlFile := range []string{"b.yaml", "a.yaml", "c.yaml"} {
+		yamlFileNodes[yamlFile] = []*yaml.RNode{yaml.MustParse(`
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+`)}
+	}
+	var b bytes.Buffer
+	err = o.migrateNodes(yamlFileNodes, &b)
+	require.Nil(t, err)
+	require.Equal(t, b.String(), `warning: a.yaml: migrating apiextensions.k8s.io/v1beta1, Kind=CustomResourceDefinition to apiextensions.k8s.io/v1, Kind=CustomResourceDefinition is not supported
+warning: b.yaml: migrating apiextensions.k8s.io/v1beta1, Kind=CustomResourceDefinition to apiextensions.k8s.io/v1, Kind=CustomResourceDefinition is not supported
+warning: c.yaml: migrating apiextensions.k8s.io/v1beta1, Kind=CustomResourceDefinition to apiextensions.k8s.io/v1, Kind=CustomResourceDefinition is not supported
+`)
+}
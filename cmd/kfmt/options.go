@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dippynark/kfmt/pkg/discovery"
 	"github.com/dippynark/kfmt/pkg/utils"
@@ -13,7 +15,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
@@ -21,6 +25,15 @@ import (
 type options struct {
 	output                  string
 	inputs                  []string
+	kustomizations          []string
+	helmChart               string
+	valuesFiles             []string
+	releaseName             string
+	downloadTimeout         time.Duration
+	downloadCacheDir        string
+	crdDirs                 []string
+	includes                []string
+	excludes                []string
 	filters                 []string
 	gvkScopes               []string
 	namespace               string
@@ -32,7 +45,32 @@ type options struct {
 	createMissingNamespaces bool
 	discovery               bool
 	kubeconfig              string
+	contexts                []string
+	cluster                 string
+	user                    string
+	discoveryCacheDir       string
+	discoveryCacheTTL       time.Duration
+	refreshDiscovery        bool
+	strictDiscovery         bool
+	disableCatalog          bool
+	kubernetesVersion       string
+	lint                    bool
+	migrateAPIs             bool
+	discoveryOrder          []string
+	scopeConflictPolicy     string
+	unknownScopePolicy      string
+	apiResources            []string
+	apiVersions             []string
 	version                 bool
+
+	// Filesystems containing the files of archive and git inputs
+	inputFss map[string]afero.Fs
+	// Revisions of git inputs by name
+	gitRevisions map[string]*gitRevision
+	// Nodes of files rendered from kustomizations and Helm charts
+	renderedFiles map[string][]*yaml.RNode
+	// Contents of remote inputs by URL
+	downloadedFiles map[string][]byte
 }
 
 func (o *options) run(fs afero.Fs) error {
@@ -43,9 +81,18 @@ func (o *options) run(fs afero.Fs) error {
 	}
 
 	// Validate options
-	if o.output == "" {
+	if o.lint && o.kubernetesVersion == "" {
+		return errors.Errorf("Kubernetes version must be specified to lint manifests")
+	}
+	if o.output == "" && !o.lint {
 		return errors.Errorf("output directory not specified")
 	}
+	if o.unknownScopePolicy != "" && !isUnknownScopePolicy(o.unknownScopePolicy) {
+		return errors.Errorf("unrecognised unknown scope policy %s", o.unknownScopePolicy)
+	}
+	if err := o.validateGlobs(); err != nil {
+		return err
+	}
 
 	// Initialise discovery to determine whether resources are namespaced or not
 	resourceInspector, err := o.getResourceInspector()
@@ -59,20 +106,69 @@ func (o *options) run(fs afero.Fs) error {
 		return err
 	}
 
+	// Find all YAML files in CRD directories, which are only used for discovery
+	crdFiles, err := o.findCRDFiles(fs)
+	if err != nil {
+		return err
+	}
+
+	// Render Helm chart templates. Chart CRDs are only used for discovery
+	templateFiles, chartCRDFiles, err := o.renderHelmChart(fs)
+	if err != nil {
+		return err
+	}
+	yamlFiles = append(yamlFiles, templateFiles...)
+	crdFiles = append(crdFiles, chartCRDFiles...)
+	yamlFiles = o.excludeFiles(yamlFiles, crdFiles)
+
 	// Map input files to nodes (parsed YAML documents)
 	yamlFileNodes, err := o.findYAMLFileNodes(fs, yamlFiles)
 	if err != nil {
 		return err
 	}
+	crdFileNodes, err := o.findYAMLFileNodes(fs, crdFiles)
+	if err != nil {
+		return err
+	}
+
+	// Report deprecated and removed APIs instead of writing output
+	if o.lint {
+		return o.lintNodes(yamlFileNodes, os.Stdout)
+	}
 
-	// Add local CRDs to discovery
-	err = o.localDiscovery(yamlFileNodes, resourceInspector)
+	// Migrate deprecated and removed APIs to their replacements
+	if o.migrateAPIs {
+		err = o.migrateNodes(yamlFileNodes, os.Stderr)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Add local CRDs to discovery. CRDs in input files are added last so that they take precedence
+	definitions := scopeDefinitions{}
+	err = o.localDiscovery(crdFileNodes, resourceInspector.Source(discovery.CRDSource), definitions)
+	if err != nil {
+		return err
+	}
+	err = o.localDiscovery(yamlFileNodes, resourceInspector.Source(discovery.CRDSource), definitions)
 	if err != nil {
 		return err
 	}
 
 	// Add manually specified GVK scopes to discovery
-	err = o.manualDiscovery(resourceInspector)
+	err = o.manualDiscovery(resourceInspector.Source(discovery.GVKScopeSource), definitions)
+	if err != nil {
+		return err
+	}
+
+	// Detect and resolve conflicting scope definitions
+	err = o.resolveScopeConflicts(definitions, resourceInspector)
+	if err != nil {
+		return err
+	}
+
+	// Apply the unknown scope policy to resources whose scope cannot be determined
+	unknown, err := o.findUnknownResources(yamlFileNodes, resourceInspector)
 	if err != nil {
 		return err
 	}
@@ -83,6 +179,12 @@ func (o *options) run(fs afero.Fs) error {
 		return err
 	}
 
+	// Detect GVKs whose scope differs between clusters once every GVK has been discovered
+	err = o.resolveClusterScopeConflicts(resourceInspector)
+	if err != nil {
+		return err
+	}
+
 	// Remove nodes that match filters
 	err = o.filterNodes(yamlFileNodes)
 	if err != nil {
@@ -108,8 +210,14 @@ func (o *options) run(fs afero.Fs) error {
 		return err
 	}
 
+	// Write quarantined nodes to disk into unknown directory
+	err = o.writeUnknownManifests(unknown, fs)
+	if err != nil {
+		return err
+	}
+
 	// Remove processed YAML files
-	err = o.removeYAMLFiles(yamlFileNodes, fs)
+	err = o.removeYAMLFiles(yamlFileNodes, o.skippedFiles(unknown), fs)
 	if err != nil {
 		return err
 	}
@@ -119,42 +227,183 @@ func (o *options) run(fs afero.Fs) error {
 		return err
 	}
 
+	// Summarise resources whose scope could not be determined
+	o.reportUnknownResources(unknown, os.Stderr)
+
 	return nil
 }
 
-func (o *options) getResourceInspector() (discovery.ResourceInspector, error) {
-	var resourceInspector discovery.ResourceInspector
+func (o *options) getResourceInspector() (*discovery.ChainResourceInspector, error) {
+	discoveryOrder := o.discoveryOrder
+	if len(discoveryOrder) == 0 {
+		discoveryOrder = discovery.DefaultSourceOrder
+	}
+	for _, name := range discoveryOrder {
+		if !isDiscoverySource(name) {
+			return nil, errors.Errorf("unrecognised discovery source %s", name)
+		}
+	}
+
+	apiResourcesFiles := o.apiResources
+	apiVersionsFiles := o.apiVersions
+	// Fall back to cached discovery files in the working directory if none are specified
+	if len(apiResourcesFiles) == 0 {
+		if _, err := os.Stat(defaultAPIResourcesFile); err == nil {
+			apiResourcesFiles = []string{defaultAPIResourcesFile}
+			if len(apiVersionsFiles) == 0 {
+				if _, err := os.Stat(defaultAPIVersionsFile); err == nil {
+					apiVersionsFiles = []string{defaultAPIVersionsFile}
+				}
+			}
+		}
+	}
+
+	localSources := map[string]discovery.LocalResourceInspectorOptions{
+		discovery.GVKScopeSource: {ExcludeCore: true},
+		discovery.CRDSource:      {ExcludeCore: true},
+		discovery.APIResourcesSource: {
+			APIResourcesFiles: apiResourcesFiles,
+			APIVersionsFiles:  apiVersionsFiles,
+			ExcludeCore:       true,
+		},
+		discovery.CoreSource: {KubernetesVersion: o.kubernetesVersion},
+	}
+	if !o.disableCatalog {
+		localSources[discovery.CatalogSource] = discovery.LocalResourceInspectorOptions{ExcludeCore: true, Catalog: true}
+	}
+
+	sources := map[string]discovery.ResourceInspector{}
+	for name, opts := range localSources {
+		opts.Strict = o.strictDiscovery
+		localResourceInspector, err := discovery.NewLocalResourceInspector(opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to construct locally backed resource inspector")
+		}
+		sources[name] = localResourceInspector
+	}
+
 	if o.discovery {
-		restcfg, err := clientcmd.BuildConfigFromFlags("", o.kubeconfig)
+		clusters, err := o.getClusters()
 		if err != nil {
-			return resourceInspector, errors.Wrap(err, "failed to build kubernetes REST client config")
+			return nil, err
 		}
-		resourceInspector, err = discovery.NewAPIServerResourceInspector(restcfg)
+		apiServerResourceInspector, err := discovery.NewAPIServerResourceInspector(clusters, nil, o.discoveryCacheDir, o.discoveryCacheTTL, o.refreshDiscovery)
 		if err != nil {
-			return resourceInspector, errors.Wrap(err, "failed to construct APIServer backed resource inspector")
+			return nil, errors.Wrap(err, "failed to construct APIServer backed resource inspector")
 		}
-	} else {
-		var err error
-		resourceInspector, err = discovery.NewLocalResourceInspector()
+		sources[discovery.APIServerSource] = apiServerResourceInspector
+	}
+
+	return discovery.NewChainResourceInspector(sources, discoveryOrder)
+}
+
+// isDiscoverySource returns true if name is a recognised discovery source
+func isDiscoverySource(name string) bool {
+	for _, source := range discovery.DefaultSourceOrder {
+		if name == source {
+			return true
+		}
+	}
+	return false
+}
+
+// getClusters returns the cluster of each kubeconfig context used for discovery, or of the current
+// context if none are specified. The cluster and user overrides are only permitted for a single
+// context since they would otherwise make every context use the same cluster or user
+func (o *options) getClusters() ([]discovery.Cluster, error) {
+	if len(o.contexts) > 1 && (o.cluster != "" || o.user != "") {
+		return nil, errors.New("cluster and user cannot be overridden when multiple contexts are specified")
+	}
+
+	contexts := o.contexts
+	if len(contexts) == 0 {
+		contexts = []string{""}
+	}
+
+	var clusters []discovery.Cluster
+	for _, context := range contexts {
+		restcfg, err := o.getRESTConfig(context)
 		if err != nil {
-			return resourceInspector, errors.Wrap(err, "failed to construct locally backed resource inspector")
+			return nil, err
 		}
+		clusters = append(clusters, discovery.Cluster{Name: context, Config: restcfg})
 	}
+	return clusters, nil
+}
 
-	return resourceInspector, nil
+// getRESTConfig loads the REST client config for context using the standard kubeconfig loading
+// rules. If context is empty the current context is used. If no kubeconfig file is specified the
+// files listed in the KUBECONFIG environment variable or ~/.kube/config are merged, falling back to
+// in-cluster configuration if none are found
+func (o *options) getRESTConfig(context string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: context,
+		Context: clientcmdapi.Context{
+			Cluster:  o.cluster,
+			AuthInfo: o.user,
+		},
+	}
+	restcfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build kubernetes REST client config")
+	}
+	return restcfg, nil
 }
 
 func (o *options) findYAMLFiles(fs afero.Fs) ([]string, error) {
+	// Input filesystems and rendered files are shared by the inputs of a run but must be read again
+	// by later runs
+	o.inputFss = nil
+	o.gitRevisions = nil
+	o.renderedFiles = nil
+
 	var yamlFiles []string
-	if len(o.inputs) > 0 {
+	if len(o.inputs) > 0 || len(o.kustomizations) > 0 || o.helmChart != "" {
 		for _, input := range o.inputs {
+			// Download remote inputs rather than reading them from the filesystem
+			if isRemoteInput(input) {
+				inputFile, err := o.downloadRemoteInput(fs, input)
+				if err != nil {
+					return yamlFiles, err
+				}
+				yamlFiles = append(yamlFiles, inputFile)
+				continue
+			}
+
+			// Read git inputs from the repository rather than the filesystem
+			if isGitInput(input) {
+				inputFiles, err := o.listGitFiles(input)
+				if err != nil {
+					return yamlFiles, err
+				}
+				yamlFiles = append(yamlFiles, inputFiles...)
+				continue
+			}
+
 			info, err := fs.Stat(input)
 			if err != nil {
 				return yamlFiles, err
 			}
 			switch mode := info.Mode(); {
 			case mode.IsDir():
-				inputFiles, err := listYAMLFiles(fs, input)
+				// Render directories containing a kustomization file
+				if kustomizationFile, ok := findKustomizationFile(fs, input); ok {
+					renderedFiles, err := o.addKustomization(fs, kustomizationFile)
+					if err != nil {
+						return yamlFiles, err
+					}
+					yamlFiles = append(yamlFiles, renderedFiles...)
+					continue
+				}
+				inputFiles, err := o.listYAMLFiles(fs, input)
+				if err != nil {
+					return yamlFiles, err
+				}
+				yamlFiles = append(yamlFiles, inputFiles...)
+			case isArchive(input):
+				inputFiles, err := o.listArchiveFiles(fs, input)
 				if err != nil {
 					return yamlFiles, err
 				}
@@ -163,6 +412,11 @@ func (o *options) findYAMLFiles(fs afero.Fs) ([]string, error) {
 				yamlFiles = append(yamlFiles, input)
 			}
 		}
+		renderedFiles, err := o.findKustomizations(fs)
+		if err != nil {
+			return yamlFiles, err
+		}
+		yamlFiles = append(yamlFiles, renderedFiles...)
 	} else {
 		// Read from stdin if no input specified
 		yamlFiles = []string{os.Stdin.Name()}
@@ -170,36 +424,98 @@ func (o *options) findYAMLFiles(fs afero.Fs) ([]string, error) {
 	return yamlFiles, nil
 }
 
+// findCRDFiles finds the YAML files in the CRD directories. The --include and --exclude patterns only
+// apply to inputs but ignore files are honoured
+func (o *options) findCRDFiles(fs afero.Fs) ([]string, error) {
+	var crdFiles []string
+	for _, crdDir := range o.crdDirs {
+		files, err := walkYAMLFiles(fs, crdDir, nil)
+		if err != nil {
+			return crdFiles, errors.Wrapf(err, "failed to list CRD directory %s", crdDir)
+		}
+		crdFiles = append(crdFiles, files...)
+	}
+	return crdFiles, nil
+}
+
 func (o *options) findYAMLFileNodes(fs afero.Fs, yamlFiles []string) (map[string][]*yaml.RNode, error) {
 	yamlFileNodes := map[string][]*yaml.RNode{}
 	for _, yamlFile := range yamlFiles {
-		b, err := afero.ReadFile(fs, yamlFile)
+		newNodes, err := o.readInputNodes(fs, yamlFile)
 		if err != nil {
 			return yamlFileNodes, err
 		}
-		newNodes, err := kio.FromBytes(b)
+		// Unwrap lists so that their items are processed individually
+		newNodes, err = utils.ExpandLists(newNodes)
 		if err != nil {
-			return yamlFileNodes, err
+			return yamlFileNodes, errors.Wrapf(err, "failed to expand lists in %s", yamlFile)
 		}
 		yamlFileNodes[yamlFile] = newNodes
 	}
 	return yamlFileNodes, nil
 }
 
-func (o *options) localDiscovery(yamlFileNodes map[string][]*yaml.RNode, resourceInspector discovery.ResourceInspector) error {
-	for yamlFile, nodes := range yamlFileNodes {
-		resources, err := findResources(nodes)
+// readInputNodes reads the nodes of an input file. The nodes of files rendered from kustomizations
+// and Helm charts have already been rendered
+func (o *options) readInputNodes(fs afero.Fs, file string) ([]*yaml.RNode, error) {
+	if nodes, ok := o.renderedFiles[file]; ok {
+		return nodes, nil
+	}
+	b, err := o.readInputFile(fs, file)
+	if err != nil {
+		return nil, err
+	}
+	return parseManifests(file, b)
+}
+
+// parseManifests parses the manifests in b. Files with a .json extension are parsed as JSON and other
+// files, such as stdin, are parsed as JSON if they look like JSON, falling back to YAML otherwise
+func parseManifests(file string, b []byte) ([]*yaml.RNode, error) {
+	if strings.HasSuffix(file, ".json") {
+		nodes, err := utils.ParseJSON(b)
+		if err != nil {
+			return nodes, errors.Wrapf(err, "failed to parse JSON in %s", file)
+		}
+		return nodes, nil
+	}
+	if utils.IsJSON(b) {
+		if nodes, err := utils.ParseJSON(b); err == nil {
+			return nodes, nil
+		}
+	}
+	return kio.FromBytes(b)
+}
+
+func (o *options) localDiscovery(yamlFileNodes map[string][]*yaml.RNode, resourceInspector discovery.ResourceInspector, definitions scopeDefinitions) error {
+	// Sort input files so that conflicting CRDs are resolved consistently
+	var yamlFiles []string
+	for yamlFile := range yamlFileNodes {
+		yamlFiles = append(yamlFiles, yamlFile)
+	}
+	sort.Strings(yamlFiles)
+
+	for _, yamlFile := range yamlFiles {
+		resources, err := findResources(yamlFileNodes[yamlFile])
 		if err != nil {
 			return errors.Wrapf(err, "failed to find CRDs in %s", yamlFile)
 		}
-		for gvk, namespaced := range resources {
-			resourceInspector.AddGVKToScope(gvk, namespaced)
+		// Later CRDs take precedence, conflicts are detected when scope conflicts are resolved
+		for _, resource := range resources {
+			resourceInspector.AddGVKToScope(resource.gvk, resource.namespaced)
+			if resource.plural != "" {
+				resourceInspector.AddGVKToPlural(resource.gvk, resource.plural)
+			}
+			definitions.add(resource.gvk, scopeDefinition{
+				namespaced: resource.namespaced,
+				source:     discovery.CRDSource,
+				origin:     fmt.Sprintf("%s, document %d", yamlFile, resource.document+1),
+			})
 		}
 	}
 	return nil
 }
 
-func (o *options) manualDiscovery(resourceInspector discovery.ResourceInspector) error {
+func (o *options) manualDiscovery(resourceInspector discovery.ResourceInspector, definitions scopeDefinitions) error {
 	for _, gvkScope := range o.gvkScopes {
 		i := strings.Index(gvkScope, ":")
 		if i == -1 {
@@ -239,6 +555,11 @@ func (o *options) manualDiscovery(resourceInspector discovery.ResourceInspector)
 		}
 
 		resourceInspector.AddGVKToScope(gvk, namespaced)
+		definitions.add(gvk, scopeDefinition{
+			namespaced: namespaced,
+			source:     discovery.GVKScopeSource,
+			origin:     gvkScope,
+		})
 	}
 
 	return nil
@@ -438,13 +759,29 @@ func (o *options) writeManifests(yamlFileNodes map[string][]*yaml.RNode, resourc
 	return nil
 }
 
-func (o *options) removeYAMLFiles(yamlFileNodes map[string][]*yaml.RNode, fs afero.Fs) error {
+func (o *options) removeYAMLFiles(yamlFileNodes map[string][]*yaml.RNode, retainedFiles map[string]struct{}, fs afero.Fs) error {
 	if o.remove {
 		for yamlFile := range yamlFileNodes {
 			// Ignore stdin
 			if yamlFile == os.Stdin.Name() {
 				continue
 			}
+			// Ignore files in archives and git revisions, which cannot be removed individually
+			if _, _, ok := o.findInputFs(yamlFile); ok {
+				continue
+			}
+			// Ignore kustomization resource files and Helm chart files since their nodes were rendered
+			if o.isRendered(yamlFile) {
+				continue
+			}
+			// Ignore remote inputs
+			if o.isDownloaded(yamlFile) {
+				continue
+			}
+			// Ignore files containing resources that were not written
+			if _, ok := retainedFiles[yamlFile]; ok {
+				continue
+			}
 			err := fs.Remove(yamlFile)
 			if err != nil {
 				return errors.Wrapf(err, "failed to remove input file %s", yamlFile)
@@ -614,35 +951,58 @@ func (o *options) findNamespaces(nodes []*yaml.RNode, resourceInspector discover
 	return namespaces, nil
 }
 
-// listYAMLFiles lists YAML files to be processed
-func listYAMLFiles(fs afero.Fs, inputDir string) ([]string, error) {
-	var files []string
+// excludeFiles returns the files that are not in excluded. Local files are compared by their cleaned
+// absolute paths so that files are matched however their directories were specified
+func (o *options) excludeFiles(files, excluded []string) []string {
+	excludedFiles := map[string]struct{}{}
+	for _, file := range excluded {
+		excludedFiles[o.comparablePath(file)] = struct{}{}
+	}
 
-	err := afero.Walk(fs, inputDir,
-		func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+	var remaining []string
+	for _, file := range files {
+		if _, ok := excludedFiles[o.comparablePath(file)]; !ok {
+			remaining = append(remaining, file)
+		}
+	}
+	return remaining
+}
 
-			// Assume regular file is valid YAML file if it has an appropriate extension
-			if info.Mode().IsRegular() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
-				files = append(files, path)
-			}
-			return nil
-		})
+// comparablePath returns the cleaned absolute path of a local file. Files that are downloaded,
+// rendered or contained in an input filesystem are returned unchanged
+func (o *options) comparablePath(file string) string {
+	if _, ok := o.downloadedFiles[file]; ok {
+		return file
+	}
+	if _, ok := o.renderedFiles[file]; ok {
+		return file
+	}
+	if _, _, ok := o.findInputFs(file); ok {
+		return file
+	}
+	absFile, err := filepath.Abs(file)
 	if err != nil {
-		return files, err
+		return filepath.Clean(file)
 	}
+	return absFile
+}
 
-	return files, err
+// resource contains discovery information for a resource defined by a CRD
+type resource struct {
+	gvk        schema.GroupVersionKind
+	namespaced bool
+	plural     string
+	// document is the index of the manifest defining the resource within its file
+	document int
 }
 
-// findResources finds resources defined as CRDs to add to discovery
-func findResources(nodes []*yaml.RNode) (map[schema.GroupVersionKind]bool, error) {
-	resources := map[schema.GroupVersionKind]bool{}
+// findResources finds resources defined as CRDs to add to discovery. Every definition is returned in
+// the order it was found so that CRDs in the same file that conflict can be detected
+func findResources(nodes []*yaml.RNode) ([]resource, error) {
+	var resources []resource
 
 	// Look for a resource definition in each manifest
-	for _, node := range nodes {
+	for document, node := range nodes {
 
 		kind, err := utils.GetKind(node)
 		if err != nil {
@@ -663,6 +1023,9 @@ func findResources(nodes []*yaml.RNode) (map[schema.GroupVersionKind]bool, error
 			return resources, err
 		}
 
+		// Plural is optional since it can be guessed from the kind
+		resourcePlural, _ := utils.GetCRDPlural(node)
+
 		resourceScope, err := utils.GetCRDScope(node)
 		if err != nil {
 			return resources, err
@@ -683,11 +1046,12 @@ func findResources(nodes []*yaml.RNode) (map[schema.GroupVersionKind]bool, error
 				Version: resourceVersion,
 				Kind:    resourceKind,
 			}
-			// TODO: should we allow discovery information to be overwritten?
-			// if _, ok := resources[gvk]; ok {
-			// 	return resources, fmt.Errorf("resource already exists: %s", gvk.String())
-			// }
-			resources[gvk] = namespaced
+			resources = append(resources, resource{
+				gvk:        gvk,
+				namespaced: namespaced,
+				plural:     resourcePlural,
+				document:   document,
+			})
 		}
 	}
 
@@ -725,10 +1089,10 @@ func (o *options) writeManifest(inputFile string, outputFile string, node *yaml.
 }
 
 func (o *options) getNonNamespacedOutputFile(name string, gvk schema.GroupVersionKind, resourceInspector discovery.ResourceInspector) string {
-	subdirectory := utils.Pluralise(strings.ToLower(gvk.Kind))
+	subdirectory := resourceInspector.Plural(gvk)
 	// Prefix with group if not core
 	if !resourceInspector.IsCoreGroup(gvk.Group) {
-		subdirectory = utils.Pluralise(strings.ToLower(gvk.Kind)) + "." + gvk.Group
+		subdirectory = resourceInspector.Plural(gvk) + "." + gvk.Group
 	}
 	return filepath.Join(o.output, nonNamespacedDirectory, subdirectory, name+".yaml")
 }
@@ -3,13 +3,19 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
@@ -178,7 +184,7 @@ metadata:
 
 	// Check that formatting fails to determine scope
 	err = o.run(fs)
-	require.Equal(t, err.Error(), "failed to find Namespaces in input.yaml: could not find REST mapping for resource test.io/v1, Kind=Tester")
+	require.Equal(t, err.Error(), "could not determine scope of 1 GVK: test.io/v1, Kind=Tester in input.yaml (gvk-scope: not found, crds: not found, catalog: not found, api-resources: not found, core: not found)")
 
 	// Create corresponding CRD and ensure resource is now formatted correctly
 	crd := `
@@ -209,6 +215,366 @@ metadata:
 	require.Nil(t, err)
 }
 
+func TestCRDDirs(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:  []string{"manifests"},
+		crdDirs: []string{"manifests/vendor"},
+		output:  outputDirectory,
+		remove:  true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests
+	err := fs.Mkdir("manifests", 0755)
+	require.Nil(t, err)
+	err = fs.Mkdir("manifests/vendor", 0755)
+	require.Nil(t, err)
+	manifests := `
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`
+	err = afero.WriteFile(fs, "manifests/input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Create corresponding CRD in a directory within the inputs
+	crd := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+spec:
+  group: test.io
+  names:
+    kind: Tester
+    plural: testerz
+  scope: Cluster
+  versions:
+  - name: v1
+`
+	err = afero.WriteFile(fs, "manifests/vendor/crd.yaml", []byte(crd), 0644)
+	require.Nil(t, err)
+
+	// Format input manifests
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "testerz.test.io/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`)
+	require.Nil(t, err)
+
+	// Check that the CRD is neither written nor removed
+	_, err = fs.Stat(path.Join(outputDirectory, nonNamespacedDirectory, "customresourcedefinitions"))
+	require.True(t, os.IsNotExist(err))
+	err = requireRegularFileContents(fs, "manifests/vendor/crd.yaml", crd)
+	require.Nil(t, err)
+
+	// Check that missing CRD directories are reported
+	o.crdDirs = []string{"missing"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to list CRD directory missing: open missing: file does not exist")
+}
+
+func TestCRDDirsPaths(t *testing.T) {
+	// Use the local filesystem so that relative and absolute paths refer to the same files
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.Nil(t, err)
+	err = os.Chdir(dir)
+	require.Nil(t, err)
+	defer os.Chdir(wd)
+	fs := afero.NewOsFs()
+
+	// Create input manifests and a CRD in a directory within the inputs
+	err = fs.MkdirAll("manifests/vendor", 0755)
+	require.Nil(t, err)
+	err = afero.WriteFile(fs, "manifests/input.yaml", []byte(`
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`), 0644)
+	require.Nil(t, err)
+	crd := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+spec:
+  group: test.io
+  names:
+    kind: Tester
+    plural: testerz
+  scope: Cluster
+  versions:
+  - name: v1
+`
+	err = afero.WriteFile(fs, "manifests/vendor/crd.yaml", []byte(crd), 0644)
+	require.Nil(t, err)
+
+	// Check that the CRD is excluded from the inputs however the directories are specified
+	for _, paths := range [][2]string{
+		{filepath.Join(dir, "manifests"), "manifests/vendor"},
+		{"manifests", filepath.Join(dir, "manifests/vendor")},
+		{"./manifests/", "manifests/../manifests/vendor/"},
+	} {
+		o := &options{
+			inputs:  []string{paths[0]},
+			crdDirs: []string{paths[1]},
+			output:  outputDirectory,
+		}
+		err = o.run(fs)
+		require.Nil(t, err)
+		err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "testerz.test.io/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`)
+		require.Nil(t, err)
+		_, err = fs.Stat(path.Join(outputDirectory, nonNamespacedDirectory, "customresourcedefinitions"))
+		require.True(t, os.IsNotExist(err), paths)
+		err = fs.RemoveAll(outputDirectory)
+		require.Nil(t, err)
+	}
+}
+
+func TestCRDPlural(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs: []string{"input.yaml"},
+		output: outputDirectory,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests with an irregular plural
+	manifests := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: proxies.test.io
+spec:
+  group: test.io
+  names:
+    kind: Proxy
+    plural: proxies
+  scope: Cluster
+  versions:
+  - name: v1
+---
+apiVersion: test.io/v1
+kind: Proxy
+metadata:
+  name: example
+---
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Format input manifests
+	err = o.run(fs)
+	require.Nil(t, err)
+
+	// Ensure cluster scoped resources are organised by their plural
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "proxies.test.io/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Proxy
+metadata:
+  name: example
+`)
+	require.Nil(t, err)
+	err = requireDirectory(fs, path.Join(outputDirectory, nonNamespacedDirectory, "ingressclasses"))
+	require.Nil(t, err)
+	err = requireDirectory(fs, path.Join(outputDirectory, nonNamespacedDirectory, "customresourcedefinitions"))
+	require.Nil(t, err)
+}
+
+func TestScopeConflicts(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:              []string{"input.yaml", "crd.yaml"},
+		output:              outputDirectory,
+		gvkScopes:           []string{"Tester.test.io/v1:Cluster"},
+		scopeConflictPolicy: scopeConflictPolicyError,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests with a CRD that conflicts with the GVK scope
+	manifests := `
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+	crd := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+spec:
+  group: test.io
+  names:
+    kind: Tester
+  scope: Namespaced
+  versions:
+  - name: v1
+`
+	err = afero.WriteFile(fs, "crd.yaml", []byte(crd), 0644)
+	require.Nil(t, err)
+
+	// Check that the conflict is reported with the source of each definition
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "conflicting scope definitions for test.io/v1, Kind=Tester: Namespaced from crds (crd.yaml, document 1), Cluster from gvk-scope (Tester.test.io/v1:Cluster)")
+
+	// Prefer the CRD definition
+	o.scopeConflictPolicy = "prefer-crds"
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/tester.test.io-example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+
+	// Check that CRDs in the same file that disagree about scope are detected
+	o = &options{
+		inputs:              []string{"input.yaml", "crds.yaml"},
+		output:              outputDirectory,
+		scopeConflictPolicy: scopeConflictPolicyError,
+	}
+	err = afero.WriteFile(fs, "crds.yaml", []byte(crd+"---"+strings.Replace(crd, "scope: Namespaced", "scope: Cluster", 1)), 0644)
+	require.Nil(t, err)
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "conflicting scope definitions for test.io/v1, Kind=Tester: Namespaced from crds (crds.yaml, document 1), Cluster from crds (crds.yaml, document 2)")
+
+	// Use unrecognised policy
+	o.scopeConflictPolicy = "prefer-foo"
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "unrecognised scope conflict policy prefer-foo")
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:            []string{"input.yaml"},
+		output:            outputDirectory,
+		strictDiscovery:   true,
+		kubernetesVersion: "1.24",
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using an API removed in Kubernetes 1.25
+	manifests := `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Format input manifests
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/cronjob-example.yaml"), `---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+
+	// Check that the API is not discovered for Kubernetes 1.25
+	o.kubernetesVersion = "1.25"
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "could not determine scope of 1 GVK: batch/v1beta1, Kind=CronJob in input.yaml (gvk-scope: not found, crds: not found, catalog: not found, api-resources: not found, core: not found)")
+
+	// Check that the API is not resolved through batch/v1 CronJob without strict discovery
+	o.strictDiscovery = false
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "could not determine scope of 1 GVK: batch/v1beta1, Kind=CronJob in input.yaml (gvk-scope: not found, crds: not found, catalog: not found, api-resources: not found, core: not found)")
+
+	// Check that other versions of core kinds are still resolved without a Kubernetes version
+	o.kubernetesVersion = ""
+	o.overwrite = true
+	err = o.run(fs)
+	require.Nil(t, err)
+}
+
+func TestCatalog(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs: []string{"input.yaml"},
+		output: outputDirectory,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using well-known CRDs
+	manifests := `
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: example
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Format input manifests
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "clusterissuers.cert-manager.io/example.yaml"), `---
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: example
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/certificate.cert-manager.io-example.yaml"), `---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+
+	// Check that the catalog is not consulted when disabled
+	o.disableCatalog = true
+	o.overwrite = true
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "could not determine scope of 2 GVKs: cert-manager.io/v1, Kind=Certificate in input.yaml (gvk-scope: not found, crds: not found, api-resources: not found, core: not found); cert-manager.io/v1, Kind=ClusterIssuer in input.yaml (gvk-scope: not found, crds: not found, api-resources: not found, core: not found)")
+}
+
 func TestNamespace(t *testing.T) {
 	// Setup options
 	o := &options{
@@ -416,6 +782,142 @@ metadata:
 	require.Nil(t, err)
 }
 
+func TestLists(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:  []string{"input.yaml"},
+		output:  outputDirectory,
+		filters: []string{"Secret"},
+		comment: true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests containing lists
+	manifests := `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: test
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: test
+    namespace: test
+---
+apiVersion: v1
+kind: ConfigMapList
+items:
+- metadata:
+    name: test
+    namespace: test
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Check that each item is formatted individually
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "namespaces/test.yaml"), `---
+# Source: input.yaml
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-test.yaml"), `---
+# Source: input.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: test
+`)
+	require.Nil(t, err)
+	_, err = fs.Stat(path.Join(outputDirectory, namespacedDirectory, "test/secret-test.yaml"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestJSON(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:  []string{"input"},
+		output:  outputDirectory,
+		comment: true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create JSON input manifests as an array followed by newline-delimited objects and in a file
+	// without a .json extension, which is detected by its contents as for stdin
+	err := fs.Mkdir("input", 0755)
+	require.Nil(t, err)
+	err = afero.WriteFile(fs, "input/manifests.json", []byte(`[{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "test"}}]
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "test", "namespace": "test"}, "data": {"enabled": "true"}}
+`), 0644)
+	require.Nil(t, err)
+	err = afero.WriteFile(fs, "input/manifests.yaml", []byte(`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "test", "namespace": "test"}}`), 0644)
+	require.Nil(t, err)
+
+	// Check that JSON manifests are written as YAML
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "namespaces/test.yaml"), `---
+# Source: input/manifests.json
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/configmap-test.yaml"), `---
+# Source: input/manifests.json
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: test
+data:
+  enabled: "true"
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "test/secret-test.yaml"), `---
+# Source: input/manifests.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+  namespace: test
+`)
+	require.Nil(t, err)
+
+	// Check that JSON files in directories that do not contain manifests are ignored
+	for file, contents := range map[string]string{
+		"input/package.json":       `{"name": "test", "version": "1.0.0"}`,
+		"input/api-resources.json": `{"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": "v1", "resources": []}`,
+		"input/openapi.json":       `{"swagger": "2.0", "definitions": {}}`,
+		"input/empty.json":         `[]`,
+		"input/invalid.json":       `{"apiVersion": "v1"`,
+	} {
+		err = afero.WriteFile(fs, file, []byte(contents), 0644)
+		require.Nil(t, err)
+	}
+	yamlFiles, err := o.findYAMLFiles(fs)
+	require.Nil(t, err)
+	require.Equal(t, yamlFiles, []string{"input/manifests.json", "input/manifests.yaml"})
+
+	// Check that invalid JSON files specified as inputs are reported
+	o.inputs = []string{"input/invalid.json"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to parse JSON in input/invalid.json: unexpected EOF")
+}
+
 func TestOverwrite(t *testing.T) {
 	// Setup options
 	o := &options{
@@ -615,4 +1117,204 @@ spec:
 	require.Equal(t, err.Error(), fmt.Sprintf("Namespace \"example\" not found when processing annotation %s", annotationNamespacesKey))
 }
 
-// TODO: Test discovery and kubeconfig
+// writeKubeconfig writes a kubeconfig containing a context, cluster and user for each name to a
+// file in dir and returns its path
+func writeKubeconfig(t *testing.T, dir, file, currentContext string, names ...string) string {
+	var clusters, users, contexts string
+	for _, name := range names {
+		clusters += fmt.Sprintf(`
+- name: %[1]s
+  cluster:
+    server: https://%[1]s.example.com`, name)
+		users += fmt.Sprintf(`
+- name: %[1]s
+  user:
+    token: %[1]s`, name)
+		contexts += fmt.Sprintf(`
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s`, name)
+	}
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: "%s"
+clusters:%s
+users:%s
+contexts:%s
+`, currentContext, clusters, users, contexts)
+
+	path := filepath.Join(dir, file)
+	err := ioutil.WriteFile(path, []byte(kubeconfig), 0644)
+	require.Nil(t, err)
+	return path
+}
+
+func TestKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	// Ensure the environment does not affect loading and is restored afterwards
+	kubeconfigEnv, kubeconfigEnvSet := os.LookupEnv(clientcmd.RecommendedConfigPathEnvVar)
+	defer func() {
+		if kubeconfigEnvSet {
+			os.Setenv(clientcmd.RecommendedConfigPathEnvVar, kubeconfigEnv)
+		} else {
+			os.Unsetenv(clientcmd.RecommendedConfigPathEnvVar)
+		}
+	}()
+	err := os.Setenv(clientcmd.RecommendedConfigPathEnvVar, filepath.Join(dir, "missing"))
+	require.Nil(t, err)
+
+	// Setup options
+	o := &options{
+		kubeconfig: writeKubeconfig(t, dir, "kubeconfig", "first", "first", "second"),
+	}
+
+	// Check that the current context is used by default
+	clusters, err := o.getClusters()
+	require.Nil(t, err)
+	require.Len(t, clusters, 1)
+	require.Equal(t, clusters[0].Config.Host, "https://first.example.com")
+	require.Equal(t, clusters[0].Config.BearerToken, "first")
+
+	// Check that contexts can be selected
+	o.contexts = []string{"second", "first"}
+	clusters, err = o.getClusters()
+	require.Nil(t, err)
+	require.Len(t, clusters, 2)
+	require.Equal(t, clusters[0].Name, "second")
+	require.Equal(t, clusters[0].Config.Host, "https://second.example.com")
+	require.Equal(t, clusters[0].Config.BearerToken, "second")
+	require.Equal(t, clusters[1].Name, "first")
+	require.Equal(t, clusters[1].Config.Host, "https://first.example.com")
+
+	// Check that the cluster and user of the context can be overridden
+	o.contexts = []string{"second"}
+	o.cluster = "first"
+	o.user = "first"
+	clusters, err = o.getClusters()
+	require.Nil(t, err)
+	require.Equal(t, clusters[0].Config.Host, "https://first.example.com")
+	require.Equal(t, clusters[0].Config.BearerToken, "first")
+
+	// Check that the cluster and user cannot be overridden for multiple contexts
+	o.contexts = []string{"second", "first"}
+	_, err = o.getClusters()
+	require.Equal(t, err.Error(), "cluster and user cannot be overridden when multiple contexts are specified")
+	o.cluster = ""
+	_, err = o.getClusters()
+	require.Equal(t, err.Error(), "cluster and user cannot be overridden when multiple contexts are specified")
+
+	// Check that missing contexts are reported
+	o = &options{
+		kubeconfig: o.kubeconfig,
+		contexts:   []string{"missing"},
+	}
+	_, err = o.getClusters()
+	require.Equal(t, err.Error(), `failed to build kubernetes REST client config: context "missing" does not exist`)
+
+	// Check that kubeconfig files listed in the environment are merged
+	err = os.Setenv(clientcmd.RecommendedConfigPathEnvVar, strings.Join([]string{
+		writeKubeconfig(t, dir, "first", "first", "first"),
+		writeKubeconfig(t, dir, "second", "", "second"),
+	}, string(filepath.ListSeparator)))
+	require.Nil(t, err)
+	o = &options{}
+	clusters, err = o.getClusters()
+	require.Nil(t, err)
+	require.Equal(t, clusters[0].Config.Host, "https://first.example.com")
+	o.contexts = []string{"second"}
+	clusters, err = o.getClusters()
+	require.Nil(t, err)
+	require.Equal(t, clusters[0].Config.Host, "https://second.example.com")
+}
+
+// newFakeAPIServer returns an API Server serving the test.io/v1 Tester resource with the given scope
+func newFakeAPIServer(t *testing.T, namespaced bool) *httptest.Server {
+	responses := map[string]string{
+		"/api":             `{"kind": "APIVersions", "versions": ["v1"]}`,
+		"/api/v1":          `{"kind": "APIResourceList", "groupVersion": "v1", "resources": [{"name": "pods", "kind": "Pod", "namespaced": true, "verbs": ["get"]}]}`,
+		"/apis":            `{"kind": "APIGroupList", "groups": [{"name": "test.io", "versions": [{"groupVersion": "test.io/v1", "version": "v1"}], "preferredVersion": {"groupVersion": "test.io/v1", "version": "v1"}}]}`,
+		"/apis/test.io/v1": fmt.Sprintf(`{"kind": "APIResourceList", "groupVersion": "test.io/v1", "resources": [{"name": "testers", "kind": "Tester", "namespaced": %t, "verbs": ["get"]}]}`, namespaced),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: first
+  cluster:
+    server: %s
+- name: second
+  cluster:
+    server: %s
+users:
+- name: user
+  user: {}
+contexts:
+- name: first
+  context:
+    cluster: first
+    user: user
+- name: second
+  context:
+    cluster: second
+    user: user
+`, newFakeAPIServer(t, true).URL, newFakeAPIServer(t, false).URL)
+	kubeconfigFile := filepath.Join(dir, "kubeconfig")
+	err := ioutil.WriteFile(kubeconfigFile, []byte(kubeconfig), 0644)
+	require.Nil(t, err)
+
+	// Setup options
+	o := &options{
+		inputs:              []string{"input.yaml"},
+		output:              outputDirectory,
+		discovery:           true,
+		kubeconfig:          kubeconfigFile,
+		contexts:            []string{"first", "second"},
+		scopeConflictPolicy: scopeConflictPolicyError,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests
+	manifests := `
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`
+	err = afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Check that formatting fails if the scope differs between clusters
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "scope of test.io/v1, Kind=Tester differs between clusters: Namespaced in first, Cluster in second")
+
+	// Check that the scope served by the first cluster is used otherwise
+	o.scopeConflictPolicy = scopeConflictPolicyWarn
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/tester.test.io-example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+}
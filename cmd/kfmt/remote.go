@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// Fragment used to pin the SHA-256 checksum of remote inputs (e.g.
+	// https://example.com/install.yaml#sha256=<checksum>)
+	checksumFragmentPrefix = "sha256="
+	// Directory of the download cache containing downloads by checksum
+	downloadCacheChecksumDirectory = "sha256"
+)
+
+// remoteInput is a manifest file downloaded over HTTP(S)
+type remoteInput struct {
+	url      string
+	checksum string
+}
+
+// isRemoteInput returns true if input is an HTTP(S) URL
+func isRemoteInput(input string) bool {
+	return strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://")
+}
+
+// parseRemoteInput parses a URL with an optional #sha256=<checksum> fragment
+func parseRemoteInput(input string) (remoteInput, error) {
+	r := remoteInput{url: input}
+	i := strings.Index(input, "#")
+	if i < 0 {
+		return r, nil
+	}
+
+	r.url = input[:i]
+	fragment := input[i+1:]
+	if !strings.HasPrefix(fragment, checksumFragmentPrefix) {
+		return r, errors.Errorf("unrecognised fragment %s in %s, expected %s<checksum>", fragment, input, checksumFragmentPrefix)
+	}
+	r.checksum = strings.ToLower(strings.TrimPrefix(fragment, checksumFragmentPrefix))
+	if b, err := hex.DecodeString(r.checksum); err != nil || len(b) != sha256.Size {
+		return r, errors.Errorf("invalid SHA-256 checksum %s in %s", r.checksum, input)
+	}
+	return r, nil
+}
+
+// downloadRemoteInput downloads a remote input, which is then read using its URL. Downloads with a
+// pinned checksum are verified and, if a download cache directory is specified, cached by checksum
+func (o *options) downloadRemoteInput(fs afero.Fs, input string) (string, error) {
+	r, err := parseRemoteInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := o.readDownloadCache(fs, r)
+	if err != nil {
+		return "", err
+	}
+	if b == nil {
+		b, err = o.download(r.url)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to download %s", r.url)
+		}
+		if r.checksum != "" {
+			if checksum := sha256Sum(b); checksum != r.checksum {
+				return "", errors.Errorf("checksum of %s is %s but expected %s", r.url, checksum, r.checksum)
+			}
+			err = o.writeDownloadCache(fs, r, b)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if o.downloadedFiles == nil {
+		o.downloadedFiles = map[string][]byte{}
+	}
+	o.downloadedFiles[r.url] = b
+	return r.url, nil
+}
+
+// download downloads url, failing if the download takes longer than the download timeout
+func (o *options) download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: o.downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// downloadCacheFile returns the download cache file of a remote input. Only remote inputs with a
+// pinned checksum are cached
+func (o *options) downloadCacheFile(r remoteInput) (string, bool) {
+	if o.downloadCacheDir == "" || r.checksum == "" {
+		return "", false
+	}
+	return filepath.Join(o.downloadCacheDir, downloadCacheChecksumDirectory, r.checksum), true
+}
+
+// readDownloadCache returns the cached contents of a remote input or nil if it has not been cached.
+// Cached contents that do not match their checksum are ignored
+func (o *options) readDownloadCache(fs afero.Fs, r remoteInput) ([]byte, error) {
+	cacheFile, ok := o.downloadCacheFile(r)
+	if !ok {
+		return nil, nil
+	}
+	b, err := afero.ReadFile(fs, cacheFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read download cache file %s", cacheFile)
+	}
+	if sha256Sum(b) != r.checksum {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// writeDownloadCache caches the contents of a remote input. Contents are written to a temporary file
+// which is renamed so that concurrent runs never read partially written files
+func (o *options) writeDownloadCache(fs afero.Fs, r remoteInput, b []byte) error {
+	cacheFile, ok := o.downloadCacheFile(r)
+	if !ok {
+		return nil
+	}
+
+	err := fs.MkdirAll(filepath.Dir(cacheFile), defaultDirectoryPerms)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create download cache directory %s", filepath.Dir(cacheFile))
+	}
+	f, err := afero.TempFile(fs, filepath.Dir(cacheFile), filepath.Base(cacheFile)+".")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary download cache file")
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = fs.Remove(f.Name())
+		return errors.Wrapf(err, "failed to write download cache file %s", cacheFile)
+	}
+	err = fs.Rename(f.Name(), cacheFile)
+	if err != nil {
+		_ = fs.Remove(f.Name())
+		return errors.Wrapf(err, "failed to write download cache file %s", cacheFile)
+	}
+	return nil
+}
+
+// isDownloaded returns true if file was downloaded rather than read from the filesystem
+func (o *options) isDownloaded(file string) bool {
+	_, ok := o.downloadedFiles[file]
+	return ok
+}
+
+func sha256Sum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteInput(t *testing.T) {
+	manifests := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+`
+	checksum := sha256Sum([]byte(manifests))
+
+	// Serve manifests, counting requests so that caching can be checked
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/install.yaml":
+			_, _ = w.Write([]byte(manifests))
+		case "/slow.yaml":
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte(manifests))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	// Setup options
+	o := &options{
+		inputs:           []string{server.URL + "/install.yaml#sha256=" + checksum},
+		output:           outputDirectory,
+		comment:          true,
+		remove:           true,
+		downloadCacheDir: "cache",
+		downloadTimeout:  time.Second,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Check that remote manifests are downloaded, verified, cached and attributed to their URL
+	err := o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-test.yaml"), `---
+# Source: `+server.URL+`/install.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join("cache", downloadCacheChecksumDirectory, checksum), manifests)
+	require.Nil(t, err)
+	require.Equal(t, requests, 1)
+
+	// Check that cached downloads are reused
+	o.overwrite = true
+	err = o.run(fs)
+	require.Nil(t, err)
+	require.Equal(t, requests, 1)
+
+	// Check that checksum mismatches are rejected
+	o.inputs = []string{server.URL + "/install.yaml#sha256=" + sha256Sum([]byte("other"))}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "checksum of "+server.URL+"/install.yaml is "+checksum+" but expected "+sha256Sum([]byte("other")))
+
+	// Check that invalid checksums are rejected
+	o.inputs = []string{server.URL + "/install.yaml#sha256=invalid"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "invalid SHA-256 checksum invalid in "+server.URL+"/install.yaml#sha256=invalid")
+
+	// Check that unsuccessful responses are reported
+	o.inputs = []string{server.URL + "/missing.yaml"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "failed to download "+server.URL+"/missing.yaml: unexpected status 404 Not Found")
+
+	// Check that slow downloads time out
+	o.inputs = []string{server.URL + "/slow.yaml"}
+	o.downloadTimeout = 10 * time.Millisecond
+	err = o.run(fs)
+	require.Contains(t, err.Error(), "failed to download "+server.URL+"/slow.yaml")
+	require.Contains(t, err.Error(), "Client.Timeout exceeded")
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dippynark/kfmt/pkg/discovery"
+	"github.com/dippynark/kfmt/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// Fail if the scope of a resource cannot be determined
+	unknownScopePolicyError = "error"
+	// Leave resources whose scope cannot be determined out of the output
+	unknownScopePolicySkip = "skip"
+	// Treat resources whose scope cannot be determined as cluster-scoped
+	unknownScopePolicyCluster = "cluster"
+	// Treat resources whose scope cannot be determined as namespaced
+	unknownScopePolicyNamespaced = "namespaced"
+	// Write resources whose scope cannot be determined unchanged into the unknown directory
+	unknownScopePolicyQuarantine = "quarantine"
+
+	// Directory containing quarantined resources
+	unknownDirectory = "unknown"
+	// Directory used for the core group when quarantining resources
+	unknownCoreGroupDirectory = "core"
+)
+
+// unknownResource is a resource whose scope cannot be determined
+type unknownResource struct {
+	inputFile string
+	node      *yaml.RNode
+}
+
+// unknownResources maps each GVK whose scope cannot be determined to its resources
+type unknownResources map[schema.GroupVersionKind][]unknownResource
+
+// gvks returns the unknown GVKs in a consistent order
+func (u unknownResources) gvks() []schema.GroupVersionKind {
+	var gvks []schema.GroupVersionKind
+	for gvk := range u {
+		gvks = append(gvks, gvk)
+	}
+	sort.Slice(gvks, func(i, j int) bool {
+		return gvks[i].String() < gvks[j].String()
+	})
+	return gvks
+}
+
+// inputFiles returns the input files containing resources of gvk
+func (u unknownResources) inputFiles(gvk schema.GroupVersionKind) []string {
+	seen := map[string]struct{}{}
+	var inputFiles []string
+	for _, resource := range u[gvk] {
+		if _, ok := seen[resource.inputFile]; ok {
+			continue
+		}
+		seen[resource.inputFile] = struct{}{}
+		inputFiles = append(inputFiles, resource.inputFile)
+	}
+	sort.Strings(inputFiles)
+	return inputFiles
+}
+
+// isUnknownScopePolicy returns true if policy is a recognised unknown scope policy
+func isUnknownScopePolicy(policy string) bool {
+	switch policy {
+	case unknownScopePolicyError, unknownScopePolicySkip, unknownScopePolicyCluster, unknownScopePolicyNamespaced, unknownScopePolicyQuarantine:
+		return true
+	}
+	return false
+}
+
+// findUnknownResources finds the resources whose scope cannot be determined and applies the unknown
+// scope policy to them. Skipped and quarantined resources are removed from yamlFileNodes. If the
+// policy is to fail, every unknown GVK is found before failing with a summary of them all
+func (o *options) findUnknownResources(yamlFileNodes map[string][]*yaml.RNode, resourceInspector discovery.ResourceInspector) (unknownResources, error) {
+	unknown := unknownResources{}
+	notFoundErrs := map[schema.GroupVersionKind]*discovery.NotFoundError{}
+	for yamlFile, nodes := range yamlFileNodes {
+		newNodes := []*yaml.RNode{}
+		for _, node := range nodes {
+			isFiltered, err := o.isFiltered(node)
+			if err != nil {
+				return unknown, err
+			}
+			if isFiltered {
+				newNodes = append(newNodes, node)
+				continue
+			}
+
+			gvk, err := utils.GetGVK(node)
+			if err != nil {
+				return unknown, err
+			}
+
+			// The scope of GVKs treated as cluster-scoped or namespaced is known once first found
+			if _, ok := unknown[gvk]; !ok {
+				_, err = resourceInspector.IsNamespaced(gvk)
+				var notFoundErr *discovery.NotFoundError
+				if !errors.As(err, &notFoundErr) {
+					newNodes = append(newNodes, node)
+					continue
+				}
+				notFoundErrs[gvk] = notFoundErr
+			}
+			unknown[gvk] = append(unknown[gvk], unknownResource{inputFile: yamlFile, node: node})
+
+			switch o.unknownScopePolicy {
+			case "", unknownScopePolicyError:
+				newNodes = append(newNodes, node)
+			case unknownScopePolicyCluster:
+				resourceInspector.AddGVKToScope(gvk, false)
+				newNodes = append(newNodes, node)
+			case unknownScopePolicyNamespaced:
+				resourceInspector.AddGVKToScope(gvk, true)
+				newNodes = append(newNodes, node)
+			}
+		}
+		yamlFileNodes[yamlFile] = newNodes
+	}
+
+	if (o.unknownScopePolicy == "" || o.unknownScopePolicy == unknownScopePolicyError) && len(unknown) > 0 {
+		var summaries []string
+		for _, gvk := range unknown.gvks() {
+			summary := fmt.Sprintf("%s in %s", gvk.String(), strings.Join(unknown.inputFiles(gvk), ", "))
+			if reasons := notFoundErrs[gvk].Reasons; len(reasons) > 0 {
+				summary = fmt.Sprintf("%s (%s)", summary, strings.Join(reasons, ", "))
+			}
+			summaries = append(summaries, summary)
+		}
+		return unknown, errors.Errorf("could not determine scope of %s: %s", countNoun(len(summaries), "GVK"), strings.Join(summaries, "; "))
+	}
+
+	return unknown, nil
+}
+
+// writeUnknownManifests writes quarantined resources unchanged into the unknown directory
+func (o *options) writeUnknownManifests(unknown unknownResources, fs afero.Fs) error {
+	if o.unknownScopePolicy != unknownScopePolicyQuarantine {
+		return nil
+	}
+
+	for _, gvk := range unknown.gvks() {
+		for _, resource := range unknown[gvk] {
+			outputFile, err := o.getUnknownOutputFile(resource.node, gvk)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get output file for resource in input file %s", resource.inputFile)
+			}
+
+			err = o.writeManifest(resource.inputFile, outputFile, resource.node, fs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getUnknownOutputFile returns the quarantine file of a resource whose scope cannot be determined.
+// Resources specifying a Namespace are separated by Namespace
+func (o *options) getUnknownOutputFile(node *yaml.RNode, gvk schema.GroupVersionKind) (string, error) {
+	name, err := utils.GetName(node)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get name")
+	}
+	namespace, err := utils.GetNamespace(node)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get namespace")
+	}
+
+	group := gvk.Group
+	if group == "" {
+		group = unknownCoreGroupDirectory
+	}
+
+	return filepath.Join(o.output, unknownDirectory, group, gvk.Kind, namespace, name+".yaml"), nil
+}
+
+// skippedFiles returns the input files containing skipped resources, which must not be removed
+func (o *options) skippedFiles(unknown unknownResources) map[string]struct{} {
+	skippedFiles := map[string]struct{}{}
+	if o.unknownScopePolicy != unknownScopePolicySkip {
+		return skippedFiles
+	}
+
+	for gvk := range unknown {
+		for _, inputFile := range unknown.inputFiles(gvk) {
+			skippedFiles[inputFile] = struct{}{}
+		}
+	}
+	return skippedFiles
+}
+
+// reportUnknownResources writes a summary of the GVKs whose scope could not be determined
+func (o *options) reportUnknownResources(unknown unknownResources, w io.Writer) {
+	for _, gvk := range unknown.gvks() {
+		var action string
+		switch o.unknownScopePolicy {
+		case unknownScopePolicySkip:
+			action = "skipped"
+		case unknownScopePolicyCluster:
+			action = "treated as cluster-scoped"
+		case unknownScopePolicyNamespaced:
+			action = "treated as namespaced"
+		case unknownScopePolicyQuarantine:
+			action = fmt.Sprintf("quarantined in %s", filepath.Join(o.output, unknownDirectory))
+		}
+		verb := "were"
+		if len(unknown[gvk]) == 1 {
+			verb = "was"
+		}
+		fmt.Fprintf(w, "warning: could not determine scope of %s in %s, %s %s %s\n", gvk.String(), strings.Join(unknown.inputFiles(gvk), ", "), countNoun(len(unknown[gvk]), "resource"), verb, action)
+	}
+}
+
+// countNoun returns count followed by noun, which is pluralised unless count is one
+func countNoun(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownScope(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:             []string{"input.yaml"},
+		output:             outputDirectory,
+		unknownScopePolicy: unknownScopePolicyQuarantine,
+		remove:             true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using unknown resources
+	manifests := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+  namespace: test
+---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`
+	err := afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+
+	// Check that unknown resources are quarantined unchanged
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-example.yaml"), `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, unknownDirectory, "test.io/Tester/test/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+  namespace: test
+`)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, unknownDirectory, "test.io/Tester/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`)
+	require.Nil(t, err)
+	_, err = fs.Stat("input.yaml")
+	require.True(t, os.IsNotExist(err))
+
+	// Check that skipped resources are not written and their input files are not removed
+	fs = afero.NewMemMapFs()
+	err = afero.WriteFile(fs, "input.yaml", []byte(manifests), 0644)
+	require.Nil(t, err)
+	o.unknownScopePolicy = unknownScopePolicySkip
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, namespacedDirectory, "default/configmap-example.yaml"), `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: example
+  namespace: default
+`)
+	require.Nil(t, err)
+	_, err = fs.Stat(path.Join(outputDirectory, unknownDirectory))
+	require.True(t, os.IsNotExist(err))
+	err = requireRegularFileContents(fs, "input.yaml", manifests)
+	require.Nil(t, err)
+
+	// Check that unknown resources can be treated as cluster-scoped
+	fs = afero.NewMemMapFs()
+	err = afero.WriteFile(fs, "input.yaml", []byte(`
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+  namespace: test
+`), 0644)
+	require.Nil(t, err)
+	o.unknownScopePolicy = unknownScopePolicyCluster
+	o.clean = true
+	err = o.run(fs)
+	require.Nil(t, err)
+	err = requireRegularFileContents(fs, path.Join(outputDirectory, nonNamespacedDirectory, "testers.test.io/example.yaml"), `---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`)
+	require.Nil(t, err)
+
+	// Check that unrecognised policies are rejected
+	o.unknownScopePolicy = "ignore"
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "unrecognised unknown scope policy ignore")
+}
+
+func TestUnknownScopeError(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:             []string{"input.yaml", "other.yaml"},
+		output:             outputDirectory,
+		unknownScopePolicy: unknownScopePolicyError,
+		disableCatalog:     true,
+		remove:             true,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using unknown resources
+	err := afero.WriteFile(fs, "input.yaml", []byte(`
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: example
+`), 0644)
+	require.Nil(t, err)
+	err = afero.WriteFile(fs, "other.yaml", []byte(`
+apiVersion: example.io/v1
+kind: Example
+metadata:
+  name: example
+`), 0644)
+	require.Nil(t, err)
+
+	// Check that every unknown GVK is reported and nothing is written or removed
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "could not determine scope of 2 GVKs: example.io/v1, Kind=Example in other.yaml (gvk-scope: not found, crds: not found, api-resources: not found, core: not found); test.io/v1, Kind=Tester in input.yaml (gvk-scope: not found, crds: not found, api-resources: not found, core: not found)")
+	_, err = fs.Stat(outputDirectory)
+	require.True(t, os.IsNotExist(err))
+	_, err = fs.Stat("input.yaml")
+	require.Nil(t, err)
+
+	// Check that a single unknown GVK is reported
+	o.inputs = []string{"other.yaml"}
+	err = o.run(fs)
+	require.Equal(t, err.Error(), "could not determine scope of 1 GVK: example.io/v1, Kind=Example in other.yaml (gvk-scope: not found, crds: not found, api-resources: not found, core: not found)")
+}
+
+func TestReportUnknownResources(t *testing.T) {
+	// Setup options
+	o := &options{
+		inputs:             []string{"input.yaml", "other.yaml"},
+		output:             outputDirectory,
+		unknownScopePolicy: unknownScopePolicyQuarantine,
+	}
+
+	// Setup memory backed filesystem
+	fs := afero.NewMemMapFs()
+
+	// Create input manifests using unknown resources
+	err := afero.WriteFile(fs, "input.yaml", []byte(`
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: first
+---
+apiVersion: test.io/v1
+kind: Tester
+metadata:
+  name: second
+`), 0644)
+	require.Nil(t, err)
+	err = afero.WriteFile(fs, "other.yaml", []byte(`
+apiVersion: example.io/v1
+kind: Example
+metadata:
+  name: example
+`), 0644)
+	require.Nil(t, err)
+
+	// Check that each unknown GVK is summarised
+	yamlFileNodes, err := o.findYAMLFileNodes(fs, o.inputs)
+	require.Nil(t, err)
+	resourceInspector, err := o.getResourceInspector()
+	require.Nil(t, err)
+	unknown, err := o.findUnknownResources(yamlFileNodes, resourceInspector)
+	require.Nil(t, err)
+	var warnings bytes.Buffer
+	o.reportUnknownResources(unknown, &warnings)
+	require.Equal(t, warnings.String(), `warning: could not determine scope of example.io/v1, Kind=Example in other.yaml, 1 resource was quarantined in output/unknown
+warning: could not determine scope of test.io/v1, Kind=Tester in input.yaml, 2 resources were quarantined in output/unknown
+`)
+}
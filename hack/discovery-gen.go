@@ -2,39 +2,116 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/dippynark/kfmt/pkg/utils"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kustomize/kyaml/kio"
 )
 
 func main() {
+	release := flag.String("release", "", "Kubernetes release of the resource definitions (e.g. 1.31). Resources removed in or before the release are excluded and the generated table is registered for the release")
+	crds := flag.Bool("crds", false, "Generate the catalog of third-party resources from the CRD manifests in the directory")
+	flag.Parse()
+	if flag.NArg() != 2 || (*crds && *release != "") {
+		fmt.Println("usage: discovery-gen [-release <release> | -crds] <directory> <output file>")
+		os.Exit(1)
+	}
 
-	// TODO: map from group/kind to namespaced (ignore version)
-	// schema.GroupKind
-	gvkToScope, err := parseGVKToScope()
+	// Versions are retained since LocalResourceInspector indexes GroupKinds itself
+	var gvkToScope map[schema.GroupVersionKind]bool
+	var gvkToPlural map[schema.GroupVersionKind]string
+	var gvkToLifecycle map[schema.GroupVersionKind]apiLifecycle
+	var err error
+	prefix := "core"
+	if *crds {
+		gvkToScope, gvkToPlural, err = parseCRDs(flag.Arg(0))
+		prefix = "catalog"
+	} else {
+		gvkToScope, gvkToLifecycle, err = parseGVKToScope(flag.Arg(0), *release)
+		gvkToPlural = map[schema.GroupVersionKind]string{}
+		for gvk := range gvkToScope {
+			gvkToPlural[gvk] = pluralise(gvk.Kind)
+		}
+	}
 	if err != nil {
 		fmt.Print(err)
 		os.Exit(1)
 	}
 
-	file, err := os.OpenFile(os.Args[len(os.Args)-1], os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	file, err := os.OpenFile(flag.Arg(1), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
 	if err != nil {
 		os.Exit(1)
 	}
 
-	file.WriteString(fmt.Sprintf("package discovery\n\n"))
-	file.WriteString(fmt.Sprintf("import \"k8s.io/apimachinery/pkg/runtime/schema\"\n\n"))
-	file.WriteString(fmt.Sprintf("var coreGVKToScope = map[schema.GroupVersionKind]bool{\n"))
-
 	var keys []schema.GroupVersionKind
 	for k := range gvkToScope {
 		keys = append(keys, k)
 	}
+	sortGVKs(keys)
+
+	file.WriteString(fmt.Sprintf("package discovery\n\n"))
+	file.WriteString(fmt.Sprintf("import \"k8s.io/apimachinery/pkg/runtime/schema\"\n\n"))
+
+	// Tables for a specific release are registered so that they can be selected at runtime
+	scopeVariable := fmt.Sprintf("var %sGVKToScope =", prefix)
+	pluralVariable := fmt.Sprintf("var %sGVKToPlural =", prefix)
+	if *release != "" {
+		file.WriteString(fmt.Sprintf("func init() {\n"))
+		file.WriteString(fmt.Sprintf("coreReleases[\"%s\"] = coreRelease{\n", *release))
+		scopeVariable = "gvkToScope:"
+		pluralVariable = "gvkToPlural:"
+	}
+
+	file.WriteString(fmt.Sprintf("%s map[schema.GroupVersionKind]bool{\n", scopeVariable))
+	for _, k := range keys {
+		file.WriteString(fmt.Sprintf("  {Group: \"%s\", Version: \"%s\", Kind: \"%s\"}: %s,\n", k.Group, k.Version, k.Kind, strconv.FormatBool(gvkToScope[k])))
+	}
+	file.WriteString(fmt.Sprintf("}"))
+	if *release != "" {
+		file.WriteString(fmt.Sprintf(","))
+	}
+	file.WriteString(fmt.Sprintf("\n\n"))
+
+	file.WriteString(fmt.Sprintf("%s map[schema.GroupVersionKind]string{\n", pluralVariable))
+	for _, k := range keys {
+		file.WriteString(fmt.Sprintf("  {Group: \"%s\", Version: \"%s\", Kind: \"%s\"}: \"%s\",\n", k.Group, k.Version, k.Kind, gvkToPlural[k]))
+	}
+	file.WriteString(fmt.Sprintf("}"))
+	if *release != "" {
+		file.WriteString(fmt.Sprintf(",\n\n"))
+
+		var lifecycleKeys []schema.GroupVersionKind
+		for k := range gvkToLifecycle {
+			lifecycleKeys = append(lifecycleKeys, k)
+		}
+		sortGVKs(lifecycleKeys)
+
+		file.WriteString(fmt.Sprintf("lifecycles: map[schema.GroupVersionKind]APILifecycle{\n"))
+		for _, k := range lifecycleKeys {
+			l := gvkToLifecycle[k]
+			file.WriteString(fmt.Sprintf("  {Group: \"%s\", Version: \"%s\", Kind: \"%s\"}: {Deprecated: \"%s\", Removed: \"%s\"", k.Group, k.Version, k.Kind, l.deprecated, l.removed))
+			if l.replacement != (schema.GroupVersionKind{}) {
+				file.WriteString(fmt.Sprintf(", Replacement: schema.GroupVersionKind{Group: \"%s\", Version: \"%s\", Kind: \"%s\"}", l.replacement.Group, l.replacement.Version, l.replacement.Kind))
+			}
+			file.WriteString(fmt.Sprintf("},\n"))
+		}
+		file.WriteString(fmt.Sprintf("},\n}\n}"))
+	}
+
+	if err := file.Close(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func sortGVKs(keys []schema.GroupVersionKind) {
 	sort.Slice(keys[:], func(i, j int) bool {
 		if keys[i].Group != keys[j].Group {
 			return keys[i].Group < keys[j].Group
@@ -44,24 +121,112 @@ func main() {
 		}
 		return keys[i].Kind < keys[j].Kind
 	})
+}
 
-	for _, k := range keys {
-		file.WriteString(fmt.Sprintf("  {Group: \"%s\", Version: \"%s\", Kind: \"%s\"}: %s,\n", k.Group, k.Version, k.Kind, strconv.FormatBool(gvkToScope[k])))
+// pluralExceptions contains kinds whose resource name does not follow the rules in pluralise
+var pluralExceptions = map[string]string{
+	"Endpoints": "endpoints",
+}
+
+// pluralise returns the resource name the API Server registers for kind
+func pluralise(kind string) string {
+	if plural, ok := pluralExceptions[kind]; ok {
+		return plural
 	}
 
-	file.WriteString(fmt.Sprintf("}"))
+	lowercaseKind := strings.ToLower(kind)
+	switch {
+	// e.g. ingress
+	case strings.HasSuffix(lowercaseKind, "s"), strings.HasSuffix(lowercaseKind, "x"), strings.HasSuffix(lowercaseKind, "ch"), strings.HasSuffix(lowercaseKind, "sh"):
+		return lowercaseKind + "es"
+	// e.g. networkpolicy
+	case strings.HasSuffix(lowercaseKind, "y") && !strings.ContainsAny(lowercaseKind[len(lowercaseKind)-2:len(lowercaseKind)-1], "aeiou"):
+		return strings.TrimSuffix(lowercaseKind, "y") + "ies"
+	}
 
-	if err := file.Close(); err != nil {
-		os.Exit(1)
+	return lowercaseKind + "s"
+}
+
+// parseCRDs returns the scope and plural of each version of the CRDs found in the YAML files in
+// crdDirectory
+func parseCRDs(crdDirectory string) (map[schema.GroupVersionKind]bool, map[schema.GroupVersionKind]string, error) {
+	gvkToScope := map[schema.GroupVersionKind]bool{}
+	gvkToPlural := map[schema.GroupVersionKind]string{}
+
+	err := filepath.Walk(crdDirectory,
+		func(fileName string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !(strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml")) {
+				return nil
+			}
+
+			b, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				return err
+			}
+			nodes, err := kio.FromBytes(b)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", fileName, err)
+			}
+
+			for _, node := range nodes {
+				kind, err := utils.GetKind(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+				if kind != "CustomResourceDefinition" {
+					continue
+				}
+
+				group, err := utils.GetCRDGroup(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+				crdKind, err := utils.GetCRDKind(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+				plural, err := utils.GetCRDPlural(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+				scope, err := utils.GetCRDScope(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+				versions, err := utils.GetCRDVersions(node)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fileName, err)
+				}
+
+				for _, version := range versions {
+					gvk := schema.GroupVersionKind{
+						Group:   group,
+						Version: version,
+						Kind:    crdKind,
+					}
+					gvkToScope[gvk] = scope == "Namespaced"
+					gvkToPlural[gvk] = plural
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return gvkToScope, gvkToPlural, err
 	}
+
+	return gvkToScope, gvkToPlural, nil
 }
 
-func extractGVKNamespacedMapping(typesFileName, group, version string) (map[schema.GroupVersionKind]bool, error) {
+func extractGVKNamespacedMapping(typesFileName, group, version, release string) (map[schema.GroupVersionKind]bool, map[schema.GroupVersionKind]apiLifecycle, error) {
 	gvkNamespaced := map[schema.GroupVersionKind]bool{}
+	gvkLifecycle := map[schema.GroupVersionKind]apiLifecycle{}
 
 	file, err := os.Open(typesFileName)
 	if err != nil {
-		return gvkNamespaced, err
+		return gvkNamespaced, gvkLifecycle, err
 	}
 	defer file.Close()
 
@@ -74,19 +239,8 @@ func extractGVKNamespacedMapping(typesFileName, group, version string) (map[sche
 				Group:   group,
 				Version: version,
 			}
-			// Determine whether type is Namespaced
 			namespaced := true
-			for scanner.Scan() {
-				line := scanner.Text()
-				// Break if line is not a comment
-				if !strings.HasPrefix(line, "//") {
-					break
-				}
-				if line == "// +genclient:nonNamespaced" {
-					namespaced = false
-					break
-				}
-			}
+			var l lifecycle
 			// Extract kind
 			for scanner.Scan() {
 				line := scanner.Text()
@@ -94,18 +248,213 @@ func extractGVKNamespacedMapping(typesFileName, group, version string) (map[sche
 				if !strings.HasPrefix(line, "//") && line != "" && !strings.HasPrefix(line, "type ") {
 					break
 				}
+				// Determine whether type is Namespaced
+				if line == "// +genclient:nonNamespaced" {
+					namespaced = false
+				}
+				// Record prerelease lifecycle
+				l.parseMarker(line)
 				if strings.HasPrefix(line, "type ") {
 					gvk.Kind = strings.Split(line, " ")[1]
+					break
 				}
 			}
 			if gvk.Kind == "" {
-				return gvkNamespaced, fmt.Errorf("Unable to find kind: %s", typesFileName)
+				return gvkNamespaced, gvkLifecycle, fmt.Errorf("Unable to find kind: %s", typesFileName)
+			}
+			l.override(lifecycleOverrides[gvk])
+			resolved, err := l.resolve(gvk)
+			if err != nil {
+				return gvkNamespaced, gvkLifecycle, fmt.Errorf("%s: %s: %w", typesFileName, gvk.Kind, err)
+			}
+			if resolved.deprecated != "" || resolved.removed != "" {
+				gvkLifecycle[gvk] = resolved
+			}
+			// Exclude types that have been removed
+			if release != "" && resolved.removed != "" {
+				removed, err := releaseAtLeast(release, resolved.removed)
+				if err != nil {
+					return gvkNamespaced, gvkLifecycle, fmt.Errorf("%s: %s: %w", typesFileName, gvk.Kind, err)
+				}
+				if removed {
+					continue
+				}
 			}
 			gvkNamespaced[gvk] = namespaced
 		}
 	}
 
-	return gvkNamespaced, nil
+	return gvkNamespaced, gvkLifecycle, nil
+}
+
+// lifecycleMarkerPrefix precedes the prerelease lifecycle markers of a type
+const lifecycleMarkerPrefix = "// +k8s:prerelease-lifecycle-gen:"
+
+// lifecycle contains the prerelease lifecycle markers of a type
+type lifecycle struct {
+	introduced  string
+	deprecated  string
+	removed     string
+	replacement string
+}
+
+// lifecycleOverrides contains prerelease lifecycle markers that correct or are missing from the
+// upstream resource definitions. Alpha versions removed before lifecycle markers were introduced
+// have no markers, so their removal is recorded here
+var lifecycleOverrides = map[schema.GroupVersionKind]lifecycle{
+	// Upstream marks IngressClassList as the replacement of IngressClass
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                {replacement: "networking.k8s.io,v1,IngressClass"},
+	{Group: "node.k8s.io", Version: "v1alpha1", Kind: "RuntimeClass"}:                     {deprecated: "1.20", removed: "1.22", replacement: "node.k8s.io,v1,RuntimeClass"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRole"}:        {deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io,v1,ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRoleBinding"}: {deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io,v1,ClusterRoleBinding"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "Role"}:               {deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io,v1,Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "RoleBinding"}:        {deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io,v1,RoleBinding"},
+	{Group: "scheduling.k8s.io", Version: "v1alpha1", Kind: "PriorityClass"}:              {deprecated: "1.14", removed: "1.22", replacement: "scheduling.k8s.io,v1,PriorityClass"},
+}
+
+// parseMarker records line if it is a prerelease lifecycle marker
+func (l *lifecycle) parseMarker(line string) {
+	if !strings.HasPrefix(line, lifecycleMarkerPrefix) {
+		return
+	}
+	i := strings.Index(line, "=")
+	if i == -1 {
+		return
+	}
+	value := line[i+1:]
+	switch strings.TrimPrefix(line[:i], lifecycleMarkerPrefix) {
+	case "introduced":
+		l.introduced = value
+	case "deprecated":
+		l.deprecated = value
+	case "removed":
+		l.removed = value
+	case "replacement":
+		l.replacement = value
+	}
+}
+
+// override replaces the markers of the type with those specified by o
+func (l *lifecycle) override(o lifecycle) {
+	if o.introduced != "" {
+		l.introduced = o.introduced
+	}
+	if o.deprecated != "" {
+		l.deprecated = o.deprecated
+	}
+	if o.removed != "" {
+		l.removed = o.removed
+	}
+	if o.replacement != "" {
+		l.replacement = o.replacement
+	}
+}
+
+// deprecatedRelease returns the release in which the type is deprecated or the empty string if it
+// has no prerelease lifecycle. As with prerelease-lifecycle-gen, types are deprecated 3 releases
+// after they are introduced unless specified
+func (l lifecycle) deprecatedRelease() (string, error) {
+	if l.deprecated != "" || l.introduced == "" {
+		return l.deprecated, nil
+	}
+	return addReleases(l.introduced, 3)
+}
+
+// removedRelease returns the release in which the type is removed or the empty string if it has no
+// prerelease lifecycle. As with prerelease-lifecycle-gen, types are removed 3 releases after they
+// are deprecated unless specified
+func (l lifecycle) removedRelease() (string, error) {
+	if l.removed != "" {
+		return l.removed, nil
+	}
+	deprecated, err := l.deprecatedRelease()
+	if err != nil || deprecated == "" {
+		return "", err
+	}
+	return addReleases(deprecated, 3)
+}
+
+// apiLifecycle contains the releases in which a type is deprecated and removed and the type that
+// replaces it
+type apiLifecycle struct {
+	deprecated  string
+	removed     string
+	replacement schema.GroupVersionKind
+}
+
+// resolve returns the lifecycle of gvk. Only prerelease versions have a lifecycle and, as with
+// prerelease-lifecycle-gen, deprecation and removal are only implied for beta versions
+func (l lifecycle) resolve(gvk schema.GroupVersionKind) (apiLifecycle, error) {
+	var resolved apiLifecycle
+	switch {
+	case strings.Contains(gvk.Version, "beta"):
+		deprecated, err := l.deprecatedRelease()
+		if err != nil {
+			return resolved, err
+		}
+		removed, err := l.removedRelease()
+		if err != nil {
+			return resolved, err
+		}
+		resolved.deprecated = deprecated
+		resolved.removed = removed
+	case strings.Contains(gvk.Version, "alpha"):
+		resolved.deprecated = l.deprecated
+		resolved.removed = l.removed
+	default:
+		return resolved, nil
+	}
+
+	// Replacements are specified as group,version,kind
+	if l.replacement != "" {
+		parts := strings.Split(l.replacement, ",")
+		if len(parts) != 3 {
+			return resolved, fmt.Errorf("failed to parse replacement %s", l.replacement)
+		}
+		resolved.replacement = schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}
+		// A type cannot be replaced by a list type
+		if strings.HasSuffix(resolved.replacement.Kind, "List") && !strings.HasSuffix(gvk.Kind, "List") {
+			return resolved, fmt.Errorf("invalid replacement %s for kind %s", l.replacement, gvk.Kind)
+		}
+	}
+
+	return resolved, nil
+}
+
+// parseRelease returns the major and minor version of release
+func parseRelease(release string) (int, int, error) {
+	var major, minor int
+	_, err := fmt.Sscanf(release, "%d.%d", &major, &minor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse release %s: %w", release, err)
+	}
+	return major, minor, nil
+}
+
+// addReleases returns the release n minor versions after release
+func addReleases(release string, n int) (string, error) {
+	major, minor, err := parseRelease(release)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d.%d", major, minor+n), nil
+}
+
+// releaseAtLeast returns true if release is the same as or later than other
+func releaseAtLeast(release, other string) (bool, error) {
+	major, minor, err := parseRelease(release)
+	if err != nil {
+		return false, err
+	}
+	otherMajor, otherMinor, err := parseRelease(other)
+	if err != nil {
+		return false, err
+	}
+
+	if major != otherMajor {
+		return major > otherMajor, nil
+	}
+	return minor >= otherMinor, nil
 }
 
 func extractSubstring(registerFileName, prefix, suffix string) (string, error) {
@@ -126,12 +475,13 @@ func extractSubstring(registerFileName, prefix, suffix string) (string, error) {
 	return "", fmt.Errorf("failed to find substring: %s %s %s", registerFileName, prefix, suffix)
 }
 
-func parseGVKToScope() (map[schema.GroupVersionKind]bool, error) {
+func parseGVKToScope(k8sDirectory, release string) (map[schema.GroupVersionKind]bool, map[schema.GroupVersionKind]apiLifecycle, error) {
 
 	gvkToScope := map[schema.GroupVersionKind]bool{}
+	gvkToLifecycle := map[schema.GroupVersionKind]apiLifecycle{}
 
 	// Walk directory containing core resource definitions
-	err := filepath.Walk(os.Args[len(os.Args)-2],
+	err := filepath.Walk(k8sDirectory,
 		func(fileName string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
@@ -149,19 +499,22 @@ func parseGVKToScope() (map[schema.GroupVersionKind]bool, error) {
 					// return err
 					return nil
 				}
-				extractedGVKNamespaced, err := extractGVKNamespacedMapping(fileName, group, version)
+				extractedGVKNamespaced, extractedGVKLifecycle, err := extractGVKNamespacedMapping(fileName, group, version, release)
 				if err != nil {
 					return err
 				}
 				for k, v := range extractedGVKNamespaced {
 					gvkToScope[k] = v
 				}
+				for k, v := range extractedGVKLifecycle {
+					gvkToLifecycle[k] = v
+				}
 			}
 			return nil
 		})
 	if err != nil {
-		return gvkToScope, err
+		return gvkToScope, gvkToLifecycle, err
 	}
 
-	return gvkToScope, nil
+	return gvkToScope, gvkToLifecycle, nil
 }
@@ -2,6 +2,9 @@ package discovery
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -13,47 +16,182 @@ import (
 
 // APIServerResourceInspector implements ResourceInspector using the Kubernetes
 // discovery API.
-// It relies on a Kubernetes apiserver that has discovery information for all
-// inputted resource types.
+// It relies on Kubernetes apiservers that together have discovery information
+// for all inputted resource types.
 type APIServerResourceInspector struct {
 	localResourceInspector *LocalResourceInspector
-	mapper                 *restmapper.DeferredDiscoveryRESTMapper
+	clusters               []apiServerCluster
+	// conflicts records the GVKs whose scope differs between clusters
+	conflicts map[schema.GroupVersionKind]*ClusterScopeConflict
 }
 
-func NewAPIServerResourceInspector(cfg *rest.Config) (*APIServerResourceInspector, error) {
-	cl, err := kdiscov.NewDiscoveryClientForConfig(cfg)
-	if err != nil {
-		return nil, err
+// Cluster is a Kubernetes apiserver consulted by an APIServerResourceInspector
+type Cluster struct {
+	// Name identifies the cluster when reporting errors and conflicts (e.g. the kubeconfig context)
+	Name   string
+	Config *rest.Config
+}
+
+// apiServerCluster contains the REST mapper of a cluster
+type apiServerCluster struct {
+	name   string
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+// ClusterScopeConflict describes a GVK whose scope differs between clusters
+type ClusterScopeConflict struct {
+	GVK schema.GroupVersionKind
+	// NamespacedClusters and ClusterScopedClusters contain the names of the clusters serving the GVK
+	// with each scope
+	NamespacedClusters    []string
+	ClusterScopedClusters []string
+}
+
+func (c *ClusterScopeConflict) String() string {
+	return fmt.Sprintf("scope of %s differs between clusters: Namespaced in %s, Cluster in %s", c.GVK.String(), strings.Join(c.NamespacedClusters, ", "), strings.Join(c.ClusterScopedClusters, ", "))
+}
+
+// NewAPIServerResourceInspector returns an APIServerResourceInspector that consults
+// localResourceInspector, if not nil, before falling back to the discovery API of each cluster.
+// Discovery information is cached in memory unless cacheDirectory is set, in which case it is
+// cached on disk for ttl
+func NewAPIServerResourceInspector(clusters []Cluster, localResourceInspector *LocalResourceInspector, cacheDirectory string, ttl time.Duration, refresh bool) (*APIServerResourceInspector, error) {
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no clusters specified")
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(cl))
-	localResourceInspector, err := NewLocalResourceInspector()
-	if err != nil {
-		return nil, err
+	// Discovery information added to an APIServerResourceInspector is held locally
+	if localResourceInspector == nil {
+		localResourceInspector = newLocalResourceInspector(true)
 	}
 
-	return &APIServerResourceInspector{
-		mapper:                 mapper,
+	a := &APIServerResourceInspector{
 		localResourceInspector: localResourceInspector,
-	}, nil
+		conflicts:              map[schema.GroupVersionKind]*ClusterScopeConflict{},
+	}
+	for _, cluster := range clusters {
+		cl, err := kdiscov.NewDiscoveryClientForConfig(cluster.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		var cachedClient kdiscov.CachedDiscoveryInterface = memory.NewMemCacheClient(cl)
+		if cacheDirectory != "" {
+			cachedClient = NewDiskCachedDiscoveryClient(cl, cacheDirectory, cluster.Config.Host, ttl, refresh)
+		}
+		a.clusters = append(a.clusters, apiServerCluster{
+			name:   cluster.Name,
+			mapper: restmapper.NewDeferredDiscoveryRESTMapper(cachedClient),
+		})
+	}
+
+	return a, nil
 }
 
+// IsNamespaced returns the scope of gvk served by the first cluster in which it is found. A
+// GVK is only reported as not found if no cluster serves it. Clusters that serve the GVK with a
+// different scope are recorded as a conflict
 func (a *APIServerResourceInspector) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
 	// First check local discovery...
 	namespaced, err := a.localResourceInspector.IsNamespaced(gvk)
-	// Ignore error if local discovery is unsuccessful
-	// TODO: use multi-error struct
 	if err == nil {
 		return namespaced, nil
 	}
 
 	// ...now check API Server discovery
-	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-	if err != nil {
-		return false, fmt.Errorf("could not find REST mapping for resource %v: %w", gvk.String(), err)
+	var reasons []string
+	var lastErr error
+	allNotFound := true
+	found := false
+	conflict := &ClusterScopeConflict{GVK: gvk}
+	for _, cluster := range a.clusters {
+		mapping, err := cluster.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			reasons = append(reasons, a.reason(cluster, err))
+			continue
+		}
+		if err != nil {
+			allNotFound = false
+			lastErr = err
+			reasons = append(reasons, a.reason(cluster, err))
+			continue
+		}
+
+		clusterNamespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		if clusterNamespaced {
+			conflict.NamespacedClusters = append(conflict.NamespacedClusters, cluster.name)
+		} else {
+			conflict.ClusterScopedClusters = append(conflict.ClusterScopedClusters, cluster.name)
+		}
+		if !found {
+			namespaced = clusterNamespaced
+			found = true
+		}
+	}
+
+	if found {
+		if len(conflict.NamespacedClusters) > 0 && len(conflict.ClusterScopedClusters) > 0 {
+			a.conflicts[gvk] = conflict
+		}
+		return namespaced, nil
+	}
+	if !allNotFound {
+		if len(a.clusters) == 1 {
+			return false, fmt.Errorf("could not find REST mapping for resource %v: %w", gvk.String(), lastErr)
+		}
+		return false, fmt.Errorf("could not find REST mapping for resource %v: %s", gvk.String(), strings.Join(reasons, ", "))
+	}
+	return false, &NotFoundError{GVK: gvk, Reasons: reasons}
+}
+
+// reason describes err returned by cluster, omitting the cluster name if there is only one
+func (a *APIServerResourceInspector) reason(cluster apiServerCluster, err error) string {
+	if len(a.clusters) == 1 {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s: %s", cluster.name, err)
+}
+
+// Conflicts returns the GVKs discovered so far whose scope differs between clusters
+func (a *APIServerResourceInspector) Conflicts() []*ClusterScopeConflict {
+	var conflicts []*ClusterScopeConflict
+	for _, conflict := range a.conflicts {
+		conflicts = append(conflicts, conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].GVK.String() < conflicts[j].GVK.String()
+	})
+	return conflicts
+}
+
+func (a *APIServerResourceInspector) Plural(gvk schema.GroupVersionKind) string {
+	plural, ok := a.plural(gvk)
+	if !ok {
+		// Guess plural as a last resort
+		return a.localResourceInspector.Plural(gvk)
+	}
+
+	return plural
+}
+
+// plural returns the plural of gvk and whether it is known
+func (a *APIServerResourceInspector) plural(gvk schema.GroupVersionKind) (string, bool) {
+	// First check local discovery...
+	plural, ok := a.localResourceInspector.plural(gvk)
+	if ok {
+		return plural, true
+	}
+
+	// ...now check API Server discovery
+	for _, cluster := range a.clusters {
+		mapping, err := cluster.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			continue
+		}
+		return mapping.Resource.Resource, true
 	}
 
-	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+	return "", false
 }
 
 func (a *APIServerResourceInspector) IsCoreGroup(group string) bool {
@@ -64,4 +202,8 @@ func (a *APIServerResourceInspector) AddGVKToScope(gvk schema.GroupVersionKind,
 	a.localResourceInspector.AddGVKToScope(gvk, namespaced)
 }
 
+func (a *APIServerResourceInspector) AddGVKToPlural(gvk schema.GroupVersionKind, plural string) {
+	a.localResourceInspector.AddGVKToPlural(gvk, plural)
+}
+
 var _ ResourceInspector = &APIServerResourceInspector{}
@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// newFakeAPIServer returns an API Server serving discovery information for apiResourceLists
+func newFakeAPIServer(t *testing.T, apiResourceLists ...metav1.APIResourceList) *httptest.Server {
+	responses := map[string]interface{}{
+		"/api": metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions", APIVersion: "v1"},
+			Versions: []string{"v1"},
+		},
+		"/api/v1": metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "Pod"}},
+		},
+	}
+	apiGroupList := metav1.APIGroupList{
+		TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+	}
+	for _, apiResourceList := range apiResourceLists {
+		gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		require.Nil(t, err)
+		groupVersion := metav1.GroupVersionForDiscovery{GroupVersion: gv.String(), Version: gv.Version}
+		apiGroupList.Groups = append(apiGroupList.Groups, metav1.APIGroup{
+			Name:             gv.Group,
+			Versions:         []metav1.GroupVersionForDiscovery{groupVersion},
+			PreferredVersion: groupVersion,
+		})
+		apiResourceList.TypeMeta = metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"}
+		responses["/apis/"+gv.String()] = apiResourceList
+	}
+	responses["/apis"] = apiGroupList
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(response)
+		require.Nil(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAPIServerResourceInspector(t *testing.T) {
+	tester := schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Tester"}
+	example := schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Example"}
+	missing := schema.GroupVersionKind{Group: "missing.io", Version: "v1", Kind: "Missing"}
+
+	first := newFakeAPIServer(t,
+		metav1.APIResourceList{
+			GroupVersion: "test.io/v1",
+			APIResources: []metav1.APIResource{{Name: "testers", Namespaced: true, Kind: "Tester"}},
+		},
+	)
+	second := newFakeAPIServer(t,
+		metav1.APIResourceList{
+			GroupVersion: "test.io/v1",
+			APIResources: []metav1.APIResource{{Name: "testers", Namespaced: false, Kind: "Tester"}},
+		},
+		metav1.APIResourceList{
+			GroupVersion: "example.io/v1",
+			APIResources: []metav1.APIResource{{Name: "examplez", Namespaced: true, Kind: "Example"}},
+		},
+	)
+
+	a, err := NewAPIServerResourceInspector([]Cluster{
+		{Name: "first", Config: &rest.Config{Host: first.URL}},
+		{Name: "second", Config: &rest.Config{Host: second.URL}},
+	}, nil, "", time.Hour, false)
+	require.Nil(t, err)
+
+	// Ensure the scope served by the first cluster is used and the conflict is recorded
+	namespaced, err := a.IsNamespaced(tester)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	require.Equal(t, a.Conflicts(), []*ClusterScopeConflict{
+		{GVK: tester, NamespacedClusters: []string{"first"}, ClusterScopedClusters: []string{"second"}},
+	})
+	require.Equal(t, a.Conflicts()[0].String(), "scope of test.io/v1, Kind=Tester differs between clusters: Namespaced in first, Cluster in second")
+
+	// Ensure GVKs served by any cluster are discovered
+	namespaced, err = a.IsNamespaced(example)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	require.Equal(t, a.Plural(example), "examplez")
+
+	// Ensure GVKs are only reported as not found if no cluster serves them
+	_, err = a.IsNamespaced(missing)
+	var notFoundErr *NotFoundError
+	require.True(t, errors.As(err, &notFoundErr))
+	require.Len(t, notFoundErr.Reasons, 2)
+	require.Contains(t, notFoundErr.Reasons[0], "first: ")
+	require.Contains(t, notFoundErr.Reasons[1], "second: ")
+}
@@ -0,0 +1,220 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	kdiscov "k8s.io/client-go/discovery"
+)
+
+const (
+	cacheFileName       = "discovery.yaml"
+	cacheFilePerms      = 0644
+	cacheDirectoryPerms = 0755
+)
+
+// Matches the characters replaced by kubectl when computing its discovery cache directory
+var overlyCautiousIllegalFileCharacters = regexp.MustCompile(`[^(\w/\.)]`)
+
+// DiskCachedDiscoveryClient implements CachedDiscoveryInterface by caching discovery information
+// retrieved from the API Server as a Snapshot on disk. Unlike the client-go disk cache, an expired
+// cache is still used if the API Server cannot be reached
+type DiskCachedDiscoveryClient struct {
+	kdiscov.DiscoveryInterface
+
+	cacheFile string
+	ttl       time.Duration
+
+	// mutex protects the variables below
+	mutex sync.Mutex
+	// refresh is true if the cache should be ignored in favour of the API Server
+	refresh bool
+	// fresh is true if all discovery information was retrieved from the API Server by this process
+	fresh bool
+}
+
+// NewDiskCachedDiscoveryClient returns a DiskCachedDiscoveryClient that caches discovery
+// information from delegate for the API Server at host in a directory under cacheDirectory. The
+// cache is considered valid for ttl unless refresh is true
+func NewDiskCachedDiscoveryClient(delegate kdiscov.DiscoveryInterface, cacheDirectory, host string, ttl time.Duration, refresh bool) *DiskCachedDiscoveryClient {
+	return &DiskCachedDiscoveryClient{
+		DiscoveryInterface: delegate,
+		cacheFile:          filepath.Join(cacheDirectory, cacheKey(host), cacheFileName),
+		ttl:                ttl,
+		refresh:            refresh,
+		fresh:              true,
+	}
+}
+
+// cacheKey converts the API Server host into a name that is safe to use as a directory
+func cacheKey(host string) string {
+	// Strip the scheme since it does not affect discovery information
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return overlyCautiousIllegalFileCharacters.ReplaceAllString(host, "_")
+}
+
+func (d *DiskCachedDiscoveryClient) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.refresh {
+		snapshot, expired, err := d.readCache()
+		if err == nil && !expired {
+			d.fresh = false
+			return snapshot.groupsAndResources()
+		}
+	}
+
+	groups, resources, err := d.DiscoveryInterface.ServerGroupsAndResources()
+	if err != nil {
+		// Fall back to the cache even if it has expired so that discovery keeps working offline
+		if snapshot, _, cacheErr := d.readCache(); cacheErr == nil {
+			d.fresh = false
+			return snapshot.groupsAndResources()
+		}
+		return groups, resources, err
+	}
+
+	// Failing to write the cache should not prevent discovery
+	_ = d.writeCache(resources)
+	d.fresh = true
+
+	return groups, resources, nil
+}
+
+func (d *DiskCachedDiscoveryClient) ServerResources() ([]*metav1.APIResourceList, error) {
+	_, resources, err := d.ServerGroupsAndResources()
+	return resources, err
+}
+
+// Fresh returns true if no cached discovery information has been used
+func (d *DiskCachedDiscoveryClient) Fresh() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.fresh
+}
+
+// Invalidate forces subsequent discovery information to be retrieved from the API Server
+func (d *DiskCachedDiscoveryClient) Invalidate() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.refresh = true
+	d.fresh = true
+}
+
+// readCache reads the cached snapshot and returns whether it has expired
+func (d *DiskCachedDiscoveryClient) readCache() (*Snapshot, bool, error) {
+	info, err := os.Stat(d.cacheFile)
+	if err != nil {
+		return nil, false, err
+	}
+	expired := time.Now().After(info.ModTime().Add(d.ttl))
+
+	documents, err := readDocuments(d.cacheFile)
+	if err != nil {
+		return nil, expired, err
+	}
+	if len(documents) != 1 {
+		return nil, expired, fmt.Errorf("%s: expected 1 document but found %d", d.cacheFile, len(documents))
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(documents[0], snapshot); err != nil {
+		return nil, expired, err
+	}
+	if snapshot.APIVersion != SnapshotAPIVersion || snapshot.Kind != SnapshotKind {
+		return nil, expired, fmt.Errorf("%s: unsupported cache format %s", d.cacheFile, snapshot.GroupVersionKind().String())
+	}
+
+	return snapshot, expired, nil
+}
+
+// writeCache atomically replaces the cached snapshot
+func (d *DiskCachedDiscoveryClient) writeCache(resources []*metav1.APIResourceList) error {
+	snapshot := newSnapshot(resources)
+
+	var b bytes.Buffer
+	if err := snapshot.Write(&b); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.cacheFile), cacheDirectoryPerms); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(d.cacheFile), filepath.Base(d.cacheFile)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(cacheFilePerms); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), d.cacheFile)
+}
+
+var _ kdiscov.CachedDiscoveryInterface = &DiskCachedDiscoveryClient{}
+
+// groupsAndResources returns the groups and resources in the snapshot in the form returned by the
+// discovery API. Versions of each group are ordered by Kubernetes version priority with the first
+// version being preferred
+func (s *Snapshot) groupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	groupVersions := map[string][]string{}
+	var groupNames []string
+	var resources []*metav1.APIResourceList
+	for i := range s.Resources {
+		gv, err := schema.ParseGroupVersion(s.Resources[i].GroupVersion)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := groupVersions[gv.Group]; !ok {
+			groupNames = append(groupNames, gv.Group)
+		}
+		groupVersions[gv.Group] = append(groupVersions[gv.Group], gv.Version)
+		resources = append(resources, &s.Resources[i])
+	}
+
+	var groups []*metav1.APIGroup
+	for _, groupName := range groupNames {
+		versions := groupVersions[groupName]
+		sort.Slice(versions, func(i, j int) bool {
+			return version.CompareKubeAwareVersionStrings(versions[i], versions[j]) > 0
+		})
+		group := &metav1.APIGroup{
+			Name: groupName,
+		}
+		for _, v := range versions {
+			group.Versions = append(group.Versions, metav1.GroupVersionForDiscovery{
+				GroupVersion: schema.GroupVersion{Group: groupName, Version: v}.String(),
+				Version:      v,
+			})
+		}
+		group.PreferredVersion = group.Versions[0]
+		groups = append(groups, group)
+	}
+
+	return groups, resources, nil
+}
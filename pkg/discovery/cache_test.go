@@ -0,0 +1,78 @@
+package discovery
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+// unreachableDiscovery simulates an API Server that cannot be reached
+type unreachableDiscovery struct {
+	*fake.FakeDiscovery
+}
+
+func (u *unreachableDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, nil, errors.New("connection refused")
+}
+
+func TestDiskCachedDiscoveryClient(t *testing.T) {
+	dir := t.TempDir()
+	host := "https://127.0.0.1:6443"
+	gvk := schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Tester"}
+
+	client := &fake.FakeDiscovery{
+		Fake: &kubetesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "test.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "testers", Namespaced: true, Kind: "Tester"},
+					},
+				},
+			},
+		},
+	}
+
+	// Warm cache
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(NewDiskCachedDiscoveryClient(client, dir, host, time.Hour, false))
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	require.Nil(t, err)
+	cacheFile := filepath.Join(dir, "127.0.0.1_6443", cacheFileName)
+	_, err = os.Stat(cacheFile)
+	require.Nil(t, err)
+
+	// Ensure cache is used while API Server is unreachable, even once expired
+	for _, ttl := range []time.Duration{time.Hour, 0} {
+		mapper = restmapper.NewDeferredDiscoveryRESTMapper(NewDiskCachedDiscoveryClient(&unreachableDiscovery{client}, dir, host, ttl, false))
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		require.Nil(t, err)
+		require.Equal(t, "testers", mapping.Resource.Resource)
+	}
+
+	// Ensure cache is refreshed when requested
+	client.Resources[0].APIResources[0].Name = "testerz"
+	mapper = restmapper.NewDeferredDiscoveryRESTMapper(NewDiskCachedDiscoveryClient(client, dir, host, time.Hour, true))
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	require.Nil(t, err)
+	require.Equal(t, "testerz", mapping.Resource.Resource)
+
+	// Ensure unknown resources cause the cache to be bypassed
+	client.Resources = append(client.Resources, &metav1.APIResourceList{
+		GroupVersion: "example.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "examples", Namespaced: false, Kind: "Example"},
+		},
+	})
+	mapper = restmapper.NewDeferredDiscoveryRESTMapper(NewDiskCachedDiscoveryClient(client, dir, host, time.Hour, false))
+	_, err = mapper.RESTMapping(schema.GroupKind{Group: "example.io", Kind: "Example"}, "v1")
+	require.Nil(t, err)
+}
@@ -0,0 +1,249 @@
+package discovery
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+var catalogGVKToScope = map[schema.GroupVersionKind]bool{
+	{Group: "acme.cert-manager.io", Version: "v1", Kind: "Challenge"}:                             true,
+	{Group: "acme.cert-manager.io", Version: "v1", Kind: "Order"}:                                 true,
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "CompositeResourceDefinition"}:    false,
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "Composition"}:                    false,
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "CompositionRevision"}:            false,
+	{Group: "apiextensions.crossplane.io", Version: "v1alpha1", Kind: "EnvironmentConfig"}:        false,
+	{Group: "apiextensions.crossplane.io", Version: "v1alpha1", Kind: "Usage"}:                    false,
+	{Group: "apiextensions.crossplane.io", Version: "v1beta1", Kind: "CompositionRevision"}:       false,
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "AppProject"}:                               true,
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}:                              true,
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}:                           true,
+	{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}:                                true,
+	{Group: "cert-manager.io", Version: "v1", Kind: "CertificateRequest"}:                         true,
+	{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"}:                              false,
+	{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}:                                     true,
+	{Group: "extensions.istio.io", Version: "v1alpha1", Kind: "WasmPlugin"}:                       true,
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "ClusterSecretStore"}:               false,
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "ExternalSecret"}:                   true,
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}:                       true,
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "SecretStore"}:                      true,
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ClusterExternalSecret"}:             false,
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ClusterSecretStore"}:                false,
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ExternalSecret"}:                    true,
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "SecretStore"}:                       true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "ACRAccessToken"}:        true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "ECRAuthorizationToken"}: true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Fake"}:                  true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "GCRAccessToken"}:        true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "GithubAccessToken"}:     true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Password"}:              true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "UUID"}:                  true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "VaultDynamicSecret"}:    true,
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Webhook"}:               true,
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Kind: "HelmRelease"}:                         true,
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2beta1", Kind: "HelmRelease"}:                    true,
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2beta2", Kind: "HelmRelease"}:                    true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImagePolicy"}:                   true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImageRepository"}:               true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImageUpdateAutomation"}:         true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImagePolicy"}:                   true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImageRepository"}:               true,
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImageUpdateAutomation"}:         true,
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Kind: "Kustomization"}:                  true,
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Kustomization"}:             true,
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Kustomization"}:             true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Alertmanager"}:                         true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}:                           true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Probe"}:                                true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus"}:                           true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}:                       true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}:                       true,
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ThanosRuler"}:                          true,
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "AlertmanagerConfig"}:             true,
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "PrometheusAgent"}:                true,
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "ScrapeConfig"}:                   true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "DestinationRule"}:                        true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "Gateway"}:                                true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "ServiceEntry"}:                           true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "Sidecar"}:                                true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "VirtualService"}:                         true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "WorkloadEntry"}:                          true,
+	{Group: "networking.istio.io", Version: "v1", Kind: "WorkloadGroup"}:                          true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "DestinationRule"}:                  true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}:                      true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Gateway"}:                          true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "ServiceEntry"}:                     true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Sidecar"}:                          true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"}:                   true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "WorkloadEntry"}:                    true,
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "WorkloadGroup"}:                    true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}:                   true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "Gateway"}:                           true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "ProxyConfig"}:                       true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "ServiceEntry"}:                      true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "Sidecar"}:                           true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}:                    true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "WorkloadEntry"}:                     true,
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "WorkloadGroup"}:                     true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1", Kind: "Receiver"}:                    true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Alert"}:                  true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Provider"}:               true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Receiver"}:               true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Alert"}:                  true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Provider"}:               true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Receiver"}:               true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Kind: "Alert"}:                  true,
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Kind: "Provider"}:               true,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Configuration"}:                            false,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "ConfigurationRevision"}:                    false,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Function"}:                                 false,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "FunctionRevision"}:                         false,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider"}:                                 false,
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "ProviderRevision"}:                         false,
+	{Group: "pkg.crossplane.io", Version: "v1alpha1", Kind: "ControllerConfig"}:                   false,
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "DeploymentRuntimeConfig"}:             false,
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "Function"}:                            false,
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "FunctionRevision"}:                    false,
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "Lock"}:                                false,
+	{Group: "secrets.crossplane.io", Version: "v1alpha1", Kind: "StoreConfig"}:                    false,
+	{Group: "security.istio.io", Version: "v1", Kind: "AuthorizationPolicy"}:                      true,
+	{Group: "security.istio.io", Version: "v1", Kind: "PeerAuthentication"}:                       true,
+	{Group: "security.istio.io", Version: "v1", Kind: "RequestAuthentication"}:                    true,
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "AuthorizationPolicy"}:                 true,
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "PeerAuthentication"}:                  true,
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "RequestAuthentication"}:               true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "Bucket"}:                            true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "GitRepository"}:                     true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmChart"}:                         true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmRepository"}:                    true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Bucket"}:                       true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "GitRepository"}:                true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "HelmChart"}:                    true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "HelmRepository"}:               true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Bucket"}:                       true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "GitRepository"}:                true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "HelmChart"}:                    true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "HelmRepository"}:               true,
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "OCIRepository"}:                true,
+	{Group: "telemetry.istio.io", Version: "v1", Kind: "Telemetry"}:                               true,
+	{Group: "telemetry.istio.io", Version: "v1alpha1", Kind: "Telemetry"}:                         true,
+}
+
+var catalogGVKToPlural = map[schema.GroupVersionKind]string{
+	{Group: "acme.cert-manager.io", Version: "v1", Kind: "Challenge"}:                             "challenges",
+	{Group: "acme.cert-manager.io", Version: "v1", Kind: "Order"}:                                 "orders",
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "CompositeResourceDefinition"}:    "compositeresourcedefinitions",
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "Composition"}:                    "compositions",
+	{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "CompositionRevision"}:            "compositionrevisions",
+	{Group: "apiextensions.crossplane.io", Version: "v1alpha1", Kind: "EnvironmentConfig"}:        "environmentconfigs",
+	{Group: "apiextensions.crossplane.io", Version: "v1alpha1", Kind: "Usage"}:                    "usages",
+	{Group: "apiextensions.crossplane.io", Version: "v1beta1", Kind: "CompositionRevision"}:       "compositionrevisions",
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "AppProject"}:                               "appprojects",
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}:                              "applications",
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}:                           "applicationsets",
+	{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}:                                "certificates",
+	{Group: "cert-manager.io", Version: "v1", Kind: "CertificateRequest"}:                         "certificaterequests",
+	{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"}:                              "clusterissuers",
+	{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}:                                     "issuers",
+	{Group: "extensions.istio.io", Version: "v1alpha1", Kind: "WasmPlugin"}:                       "wasmplugins",
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "ClusterSecretStore"}:               "clustersecretstores",
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "ExternalSecret"}:                   "externalsecrets",
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}:                       "pushsecrets",
+	{Group: "external-secrets.io", Version: "v1alpha1", Kind: "SecretStore"}:                      "secretstores",
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ClusterExternalSecret"}:             "clusterexternalsecrets",
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ClusterSecretStore"}:                "clustersecretstores",
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "ExternalSecret"}:                    "externalsecrets",
+	{Group: "external-secrets.io", Version: "v1beta1", Kind: "SecretStore"}:                       "secretstores",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "ACRAccessToken"}:        "acraccesstokens",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "ECRAuthorizationToken"}: "ecrauthorizationtokens",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Fake"}:                  "fakes",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "GCRAccessToken"}:        "gcraccesstokens",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "GithubAccessToken"}:     "githubaccesstokens",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Password"}:              "passwords",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "UUID"}:                  "uuids",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "VaultDynamicSecret"}:    "vaultdynamicsecrets",
+	{Group: "generators.external-secrets.io", Version: "v1alpha1", Kind: "Webhook"}:               "webhooks",
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Kind: "HelmRelease"}:                         "helmreleases",
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2beta1", Kind: "HelmRelease"}:                    "helmreleases",
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2beta2", Kind: "HelmRelease"}:                    "helmreleases",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImagePolicy"}:                   "imagepolicies",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImageRepository"}:               "imagerepositories",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta1", Kind: "ImageUpdateAutomation"}:         "imageupdateautomations",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImagePolicy"}:                   "imagepolicies",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImageRepository"}:               "imagerepositories",
+	{Group: "image.toolkit.fluxcd.io", Version: "v1beta2", Kind: "ImageUpdateAutomation"}:         "imageupdateautomations",
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Kind: "Kustomization"}:                  "kustomizations",
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Kustomization"}:             "kustomizations",
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Kustomization"}:             "kustomizations",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Alertmanager"}:                         "alertmanagers",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}:                           "podmonitors",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Probe"}:                                "probes",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus"}:                           "prometheuses",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}:                       "prometheusrules",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}:                       "servicemonitors",
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ThanosRuler"}:                          "thanosrulers",
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "AlertmanagerConfig"}:             "alertmanagerconfigs",
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "PrometheusAgent"}:                "prometheusagents",
+	{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "ScrapeConfig"}:                   "scrapeconfigs",
+	{Group: "networking.istio.io", Version: "v1", Kind: "DestinationRule"}:                        "destinationrules",
+	{Group: "networking.istio.io", Version: "v1", Kind: "Gateway"}:                                "gateways",
+	{Group: "networking.istio.io", Version: "v1", Kind: "ServiceEntry"}:                           "serviceentries",
+	{Group: "networking.istio.io", Version: "v1", Kind: "Sidecar"}:                                "sidecars",
+	{Group: "networking.istio.io", Version: "v1", Kind: "VirtualService"}:                         "virtualservices",
+	{Group: "networking.istio.io", Version: "v1", Kind: "WorkloadEntry"}:                          "workloadentries",
+	{Group: "networking.istio.io", Version: "v1", Kind: "WorkloadGroup"}:                          "workloadgroups",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "DestinationRule"}:                  "destinationrules",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}:                      "envoyfilters",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Gateway"}:                          "gateways",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "ServiceEntry"}:                     "serviceentries",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Sidecar"}:                          "sidecars",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"}:                   "virtualservices",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "WorkloadEntry"}:                    "workloadentries",
+	{Group: "networking.istio.io", Version: "v1alpha3", Kind: "WorkloadGroup"}:                    "workloadgroups",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}:                   "destinationrules",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "Gateway"}:                           "gateways",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "ProxyConfig"}:                       "proxyconfigs",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "ServiceEntry"}:                      "serviceentries",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "Sidecar"}:                           "sidecars",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}:                    "virtualservices",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "WorkloadEntry"}:                     "workloadentries",
+	{Group: "networking.istio.io", Version: "v1beta1", Kind: "WorkloadGroup"}:                     "workloadgroups",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1", Kind: "Receiver"}:                    "receivers",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Alert"}:                  "alerts",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Provider"}:               "providers",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Receiver"}:               "receivers",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Alert"}:                  "alerts",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Provider"}:               "providers",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Receiver"}:               "receivers",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Kind: "Alert"}:                  "alerts",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Kind: "Provider"}:               "providers",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Configuration"}:                            "configurations",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "ConfigurationRevision"}:                    "configurationrevisions",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Function"}:                                 "functions",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "FunctionRevision"}:                         "functionrevisions",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "Provider"}:                                 "providers",
+	{Group: "pkg.crossplane.io", Version: "v1", Kind: "ProviderRevision"}:                         "providerrevisions",
+	{Group: "pkg.crossplane.io", Version: "v1alpha1", Kind: "ControllerConfig"}:                   "controllerconfigs",
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "DeploymentRuntimeConfig"}:             "deploymentruntimeconfigs",
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "Function"}:                            "functions",
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "FunctionRevision"}:                    "functionrevisions",
+	{Group: "pkg.crossplane.io", Version: "v1beta1", Kind: "Lock"}:                                "locks",
+	{Group: "secrets.crossplane.io", Version: "v1alpha1", Kind: "StoreConfig"}:                    "storeconfigs",
+	{Group: "security.istio.io", Version: "v1", Kind: "AuthorizationPolicy"}:                      "authorizationpolicies",
+	{Group: "security.istio.io", Version: "v1", Kind: "PeerAuthentication"}:                       "peerauthentications",
+	{Group: "security.istio.io", Version: "v1", Kind: "RequestAuthentication"}:                    "requestauthentications",
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "AuthorizationPolicy"}:                 "authorizationpolicies",
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "PeerAuthentication"}:                  "peerauthentications",
+	{Group: "security.istio.io", Version: "v1beta1", Kind: "RequestAuthentication"}:               "requestauthentications",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "Bucket"}:                            "buckets",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "GitRepository"}:                     "gitrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmChart"}:                         "helmcharts",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "HelmRepository"}:                    "helmrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "Bucket"}:                       "buckets",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "GitRepository"}:                "gitrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "HelmChart"}:                    "helmcharts",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "HelmRepository"}:               "helmrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "Bucket"}:                       "buckets",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "GitRepository"}:                "gitrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "HelmChart"}:                    "helmcharts",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "HelmRepository"}:               "helmrepositories",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Kind: "OCIRepository"}:                "ocirepositories",
+	{Group: "telemetry.istio.io", Version: "v1", Kind: "Telemetry"}:                               "telemetries",
+	{Group: "telemetry.istio.io", Version: "v1alpha1", Kind: "Telemetry"}:                         "telemetries",
+}
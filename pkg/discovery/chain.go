@@ -0,0 +1,163 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dippynark/kfmt/pkg/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Names of the sources of discovery information consulted by a ChainResourceInspector
+const (
+	// GVKScopeSource contains manually specified GVK scopes
+	GVKScopeSource = "gvk-scope"
+	// CRDSource contains resources defined by CRDs
+	CRDSource = "crds"
+	// CatalogSource contains the embedded discovery information for well-known CRDs
+	CatalogSource = "catalog"
+	// APIResourcesSource contains cached discovery files
+	APIResourcesSource = "api-resources"
+	// CoreSource contains the embedded discovery information for core resources
+	CoreSource = "core"
+	// APIServerSource is the Kubernetes discovery API
+	APIServerSource = "api-server"
+)
+
+// DefaultSourceOrder is the order in which sources are consulted by default
+var DefaultSourceOrder = []string{GVKScopeSource, CRDSource, CatalogSource, APIResourcesSource, CoreSource, APIServerSource}
+
+// pluralInspector is implemented by ResourceInspectors that can report whether a plural has been
+// discovered rather than guessed
+type pluralInspector interface {
+	plural(schema.GroupVersionKind) (string, bool)
+}
+
+// ChainResourceInspector implements ResourceInspector by consulting a number of named
+// ResourceInspectors in order
+type ChainResourceInspector struct {
+	sources map[string]ResourceInspector
+	order   []string
+}
+
+// NewChainResourceInspector returns a ChainResourceInspector that consults sources in the given
+// order. Names in order without a corresponding source are skipped
+func NewChainResourceInspector(sources map[string]ResourceInspector, order []string) (*ChainResourceInspector, error) {
+	seen := map[string]struct{}{}
+	for _, name := range order {
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("discovery source %s specified more than once", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	return &ChainResourceInspector{
+		sources: sources,
+		order:   order,
+	}, nil
+}
+
+// Source returns the named source, which may not be consulted if it is not in the chain's order
+func (c *ChainResourceInspector) Source(name string) ResourceInspector {
+	return c.sources[name]
+}
+
+// Order returns the order in which sources are consulted
+func (c *ChainResourceInspector) Order() []string {
+	return c.order
+}
+
+// IsNamespaced returns the scope reported by the first source that can discover gvk. If no source
+// can discover gvk then the returned error lists what each source reported
+func (c *ChainResourceInspector) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	var reasons []string
+	allNotFound := true
+	for _, name := range c.order {
+		source, ok := c.sources[name]
+		if !ok {
+			continue
+		}
+
+		namespaced, err := source.IsNamespaced(gvk)
+		if err == nil {
+			return namespaced, nil
+		}
+
+		var notFoundErr *NotFoundError
+		if errors.As(err, &notFoundErr) {
+			reason := "not found"
+			if len(notFoundErr.Reasons) > 0 {
+				reason = strings.Join(notFoundErr.Reasons, "; ")
+			}
+			reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+		} else {
+			allNotFound = false
+			reasons = append(reasons, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	if !allNotFound {
+		// Do not report the GVK as unknown if a source failed for another reason
+		return false, fmt.Errorf("could not find REST mapping for resource %v: %s", gvk.String(), strings.Join(reasons, ", "))
+	}
+	return false, &NotFoundError{GVK: gvk, Reasons: reasons}
+}
+
+// Plural returns the plural reported by the first source that has discovered it, guessing it from
+// the kind otherwise
+func (c *ChainResourceInspector) Plural(gvk schema.GroupVersionKind) string {
+	plural, ok := c.plural(gvk)
+	if !ok {
+		// Guess plural as a last resort
+		return utils.Pluralise(strings.ToLower(gvk.Kind))
+	}
+
+	return plural
+}
+
+// plural returns the plural of gvk and whether it is known
+func (c *ChainResourceInspector) plural(gvk schema.GroupVersionKind) (string, bool) {
+	for _, name := range c.order {
+		source, ok := c.sources[name].(pluralInspector)
+		if !ok {
+			continue
+		}
+		if plural, ok := source.plural(gvk); ok {
+			return plural, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *ChainResourceInspector) IsCoreGroup(group string) bool {
+	return isCoreGroup(group)
+}
+
+// AddGVKToScope adds GVK scope mapping to the first source consulted
+func (c *ChainResourceInspector) AddGVKToScope(gvk schema.GroupVersionKind, namespaced bool) {
+	if source := c.first(); source != nil {
+		source.AddGVKToScope(gvk, namespaced)
+	}
+}
+
+// AddGVKToPlural adds GVK plural mapping to the first source consulted
+func (c *ChainResourceInspector) AddGVKToPlural(gvk schema.GroupVersionKind, plural string) {
+	if source := c.first(); source != nil {
+		source.AddGVKToPlural(gvk, plural)
+	}
+}
+
+// first returns the first source consulted
+func (c *ChainResourceInspector) first() ResourceInspector {
+	for _, name := range c.order {
+		if source, ok := c.sources[name]; ok {
+			return source
+		}
+	}
+
+	return nil
+}
+
+var _ ResourceInspector = &ChainResourceInspector{}
@@ -0,0 +1,75 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// failingResourceInspector simulates a source that cannot be consulted
+type failingResourceInspector struct {
+	*LocalResourceInspector
+}
+
+func (f *failingResourceInspector) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestChainResourceInspector(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Tester"}
+
+	first, err := NewLocalResourceInspector(LocalResourceInspectorOptions{ExcludeCore: true})
+	require.Nil(t, err)
+	second, err := NewLocalResourceInspector(LocalResourceInspectorOptions{ExcludeCore: true})
+	require.Nil(t, err)
+	sources := map[string]ResourceInspector{
+		GVKScopeSource: first,
+		CRDSource:      second,
+	}
+	first.AddGVKToScope(gvk, true)
+	second.AddGVKToScope(gvk, false)
+	second.AddGVKToPlural(gvk, "testerz")
+
+	// Ensure sources are consulted in order
+	c, err := NewChainResourceInspector(sources, []string{GVKScopeSource, CRDSource})
+	require.Nil(t, err)
+	namespaced, err := c.IsNamespaced(gvk)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	require.Equal(t, "testerz", c.Plural(gvk))
+
+	c, err = NewChainResourceInspector(sources, []string{CRDSource, GVKScopeSource})
+	require.Nil(t, err)
+	namespaced, err = c.IsNamespaced(gvk)
+	require.Nil(t, err)
+	require.False(t, namespaced)
+
+	// Ensure omitted sources are not consulted
+	c, err = NewChainResourceInspector(sources, []string{CoreSource, GVKScopeSource})
+	require.Nil(t, err)
+	namespaced, err = c.IsNamespaced(gvk)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	require.Equal(t, "testers", c.Plural(gvk))
+
+	// Ensure every source is reported on failure
+	unknownGVK := schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Unknown"}
+	c, err = NewChainResourceInspector(sources, DefaultSourceOrder)
+	require.Nil(t, err)
+	_, err = c.IsNamespaced(unknownGVK)
+	require.EqualError(t, err, "could not find REST mapping for resource test.io/v1, Kind=Unknown: gvk-scope: not found, crds: not found")
+	var notFoundErr *NotFoundError
+	require.True(t, errors.As(err, &notFoundErr))
+
+	// Ensure failures other than missing resources are distinguished
+	sources[APIServerSource] = &failingResourceInspector{first}
+	_, err = c.IsNamespaced(unknownGVK)
+	require.EqualError(t, err, "could not find REST mapping for resource test.io/v1, Kind=Unknown: gvk-scope: not found, crds: not found, api-server: connection refused")
+	require.False(t, errors.As(err, &notFoundErr))
+
+	// Ensure sources cannot be repeated
+	_, err = NewChainResourceInspector(sources, []string{CRDSource, CRDSource})
+	require.EqualError(t, err, "discovery source crds specified more than once")
+}
@@ -0,0 +1,25 @@
+package discovery
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NotFoundError is returned when the scope of a GroupVersionKind cannot be
+// discovered
+type NotFoundError struct {
+	GVK schema.GroupVersionKind
+	// Reasons describes why each consulted source could not discover the
+	// GroupVersionKind
+	Reasons []string
+}
+
+func (e *NotFoundError) Error() string {
+	message := fmt.Sprintf("could not find REST mapping for resource %v", e.GVK.String())
+	if len(e.Reasons) > 0 {
+		message += ": " + strings.Join(e.Reasons, ", ")
+	}
+	return message
+}
@@ -10,6 +10,11 @@ type ResourceInspector interface {
 	IsNamespaced(schema.GroupVersionKind) (bool, error)
 	// AddGVKToScope adds GVK scope mapping to discovery
 	AddGVKToScope(schema.GroupVersionKind, bool)
+	// Plural returns the plural resource name of the given GroupVersionKind,
+	// guessing it from the kind if it cannot be discovered
+	Plural(schema.GroupVersionKind) string
+	// AddGVKToPlural adds GVK plural resource name mapping to discovery
+	AddGVKToPlural(schema.GroupVersionKind, string)
 	// IsCoreGroup returns true if group is core
 	IsCoreGroup(string) bool
 }
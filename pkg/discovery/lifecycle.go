@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// APILifecycle contains the Kubernetes releases in which a prerelease core API is deprecated and
+// removed
+type APILifecycle struct {
+	Deprecated string
+	Removed    string
+	// Replacement is the API that should be used instead, if known
+	Replacement schema.GroupVersionKind
+}
+
+// DeprecatedIn returns true if the API is deprecated but not yet removed in kubernetesVersion
+func (l APILifecycle) DeprecatedIn(kubernetesVersion *utilversion.Version) bool {
+	return releaseAtLeast(kubernetesVersion, l.Deprecated) && !l.RemovedIn(kubernetesVersion)
+}
+
+// RemovedIn returns true if the API is removed in kubernetesVersion
+func (l APILifecycle) RemovedIn(kubernetesVersion *utilversion.Version) bool {
+	return releaseAtLeast(kubernetesVersion, l.Removed)
+}
+
+// releaseAtLeast returns true if kubernetesVersion belongs to release or a later release
+func releaseAtLeast(kubernetesVersion *utilversion.Version, release string) bool {
+	if release == "" {
+		return false
+	}
+	v, err := utilversion.ParseGeneric(release)
+	if err != nil {
+		return false
+	}
+	return kubernetesVersion.AtLeast(v)
+}
+
+// GetAPILifecycle returns the lifecycle of gvk if it is a prerelease core API that is deprecated
+// or removed in any supported Kubernetes release
+func GetAPILifecycle(gvk schema.GroupVersionKind) (APILifecycle, bool) {
+	var lifecycle APILifecycle
+	found := false
+	// Later releases take precedence
+	for _, release := range KubernetesVersions() {
+		if l, ok := coreReleases[release].lifecycles[gvk]; ok {
+			lifecycle = l
+			found = true
+		}
+	}
+
+	return lifecycle, found
+}
@@ -4,86 +4,267 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/dippynark/kfmt/pkg/utils"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 )
 
 // LocalResourceInspector implements ResourceInspector using local manifests
 type LocalResourceInspector struct {
-	gvkToScope map[schema.GroupVersionKind]bool
+	gvkToScope  map[schema.GroupVersionKind]bool
+	gvkToPlural map[schema.GroupVersionKind]string
+	// Scope and plural do not change across versions so GroupKinds are indexed to support
+	// versions that have not been discovered
+	gkToScope  map[schema.GroupKind]bool
+	gkToPlural map[schema.GroupKind]string
+	strict     bool
+	// versionedCore disables falling back to other versions of core GroupKinds since the core
+	// resources of the selected Kubernetes release are known exactly
+	versionedCore bool
 }
 
-func NewLocalResourceInspector() (*LocalResourceInspector, error) {
-	cachedGVKToScope, err := parseCachedAPIResources()
+// LocalResourceInspectorOptions configures a LocalResourceInspector
+type LocalResourceInspectorOptions struct {
+	// APIResourcesFiles contain the output of `kubectl api-resources`. Files with a .json, .yaml or
+	// .yml extension are instead parsed as DiscoverySnapshot, APIResourceList,
+	// APIGroupDiscoveryList or OpenAPI v2 and v3 documents. Files are merged in order so later files take precedence
+	APIResourcesFiles []string
+	// APIVersionsFiles contain the output of `kubectl api-versions`. Files with a .json, .yaml or
+	// .yml extension are instead parsed as APIGroupList documents
+	APIVersionsFiles []string
+	// Strict requires an exact GroupVersionKind match rather than falling back to other versions
+	// of the same GroupKind
+	Strict bool
+	// ExcludeCore excludes the embedded discovery information for core resources
+	ExcludeCore bool
+	// KubernetesVersion selects the Kubernetes release whose core resources are embedded (e.g. 1.31
+	// or v1.31.2). If empty, core resources are not specific to a release
+	KubernetesVersion string
+	// Catalog includes the embedded discovery information for the CRDs of well-known projects
+	Catalog bool
+}
+
+// coreRelease contains discovery information for the core resources of a Kubernetes release
+type coreRelease struct {
+	gvkToScope  map[schema.GroupVersionKind]bool
+	gvkToPlural map[schema.GroupVersionKind]string
+	// lifecycles contains the lifecycle of prerelease APIs including those that have been removed
+	lifecycles map[schema.GroupVersionKind]APILifecycle
+}
+
+// coreReleases contains the generated core discovery information for each supported Kubernetes
+// release indexed by major and minor version
+var coreReleases = map[string]coreRelease{}
+
+// KubernetesVersions returns the Kubernetes releases with embedded core discovery information
+func KubernetesVersions() []string {
+	var releases []string
+	for release := range coreReleases {
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return utilversion.MustParseGeneric(releases[i]).LessThan(utilversion.MustParseGeneric(releases[j]))
+	})
+
+	return releases
+}
+
+// getCoreRelease returns the core discovery information for the release of kubernetesVersion
+func getCoreRelease(kubernetesVersion string) (coreRelease, error) {
+	if kubernetesVersion == "" {
+		return coreRelease{gvkToScope: coreGVKToScope, gvkToPlural: coreGVKToPlural}, nil
+	}
+
+	v, err := utilversion.ParseGeneric(kubernetesVersion)
+	if err != nil {
+		return coreRelease{}, fmt.Errorf("failed to parse Kubernetes version: %w", err)
+	}
+	release := fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	core, ok := coreReleases[release]
+	if !ok {
+		return coreRelease{}, fmt.Errorf("unsupported Kubernetes version %s, supported versions are %s", kubernetesVersion, strings.Join(KubernetesVersions(), ", "))
+	}
+
+	return core, nil
+}
+
+func newLocalResourceInspector(strict bool) *LocalResourceInspector {
+	return &LocalResourceInspector{
+		gvkToScope:  map[schema.GroupVersionKind]bool{},
+		gvkToPlural: map[schema.GroupVersionKind]string{},
+		gkToScope:   map[schema.GroupKind]bool{},
+		gkToPlural:  map[schema.GroupKind]string{},
+		strict:      strict,
+	}
+}
+
+// NewLocalResourceInspector returns a LocalResourceInspector that understands core resources and
+// any resources found in the cached discovery files specified by opts
+func NewLocalResourceInspector(opts LocalResourceInspectorOptions) (*LocalResourceInspector, error) {
+	cached, err := parseCachedAPIResources(opts.APIResourcesFiles, opts.APIVersionsFiles)
 	if err != nil {
 		return nil, err
 	}
 
-	gvkToScope := copyMap(coreGVKToScope)
-	for k, v := range cachedGVKToScope {
-		gvkToScope[k] = v
+	l := newLocalResourceInspector(opts.Strict)
+	l.versionedCore = !opts.ExcludeCore && opts.KubernetesVersion != ""
+	if !opts.ExcludeCore {
+		core, err := getCoreRelease(opts.KubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range core.gvkToScope {
+			l.AddGVKToScope(k, v)
+		}
+		for k, v := range core.gvkToPlural {
+			l.AddGVKToPlural(k, v)
+		}
 	}
+	if opts.Catalog {
+		for k, v := range catalogGVKToScope {
+			l.AddGVKToScope(k, v)
+		}
+		for k, v := range catalogGVKToPlural {
+			l.AddGVKToPlural(k, v)
+		}
+	}
+	l.merge(cached)
 
-	return &LocalResourceInspector{
-		gvkToScope: gvkToScope,
-	}, nil
+	return l, nil
 }
 
 func (l *LocalResourceInspector) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
 	namespaced, ok := l.gvkToScope[gvk]
+	if ok {
+		return namespaced, nil
+	}
+
+	if l.fallback(gvk) {
+		namespaced, ok = l.gkToScope[gvk.GroupKind()]
+		if ok {
+			return namespaced, nil
+		}
+	}
+
+	return false, &NotFoundError{GVK: gvk}
+}
+
+func (l *LocalResourceInspector) Plural(gvk schema.GroupVersionKind) string {
+	plural, ok := l.plural(gvk)
 	if !ok {
-		return false, fmt.Errorf("could not find REST mapping for resource %v", gvk.String())
+		// Guess plural as a last resort
+		return utils.Pluralise(strings.ToLower(gvk.Kind))
 	}
 
-	return namespaced, nil
+	return plural
 }
 
-func (l *LocalResourceInspector) IsCoreGroup(group string) bool {
-	for gvk, _ := range coreGVKToScope {
-		if group == gvk.Group {
-			return true
-		}
+// plural returns the plural of gvk and whether it is known
+func (l *LocalResourceInspector) plural(gvk schema.GroupVersionKind) (string, bool) {
+	plural, ok := l.gvkToPlural[gvk]
+	if ok || !l.fallback(gvk) {
+		return plural, ok
 	}
 
-	return false
+	plural, ok = l.gkToPlural[gvk.GroupKind()]
+	return plural, ok
+}
+
+// fallback returns true if gvk may be resolved using other versions of its GroupKind. Versions of
+// core GroupKinds that are removed in or added after the selected Kubernetes release are not
+// resolved
+func (l *LocalResourceInspector) fallback(gvk schema.GroupVersionKind) bool {
+	return !l.strict && !(l.versionedCore && isCoreGroup(gvk.Group))
+}
+
+func (l *LocalResourceInspector) IsCoreGroup(group string) bool {
+	return isCoreGroup(group)
 }
 
 func (l *LocalResourceInspector) AddGVKToScope(gvk schema.GroupVersionKind, namespaced bool) {
 	l.gvkToScope[gvk] = namespaced
+	l.gkToScope[gvk.GroupKind()] = namespaced
+}
+
+func (l *LocalResourceInspector) AddGVKToPlural(gvk schema.GroupVersionKind, plural string) {
+	l.gvkToPlural[gvk] = plural
+	l.gkToPlural[gvk.GroupKind()] = plural
 }
 
 var _ ResourceInspector = &LocalResourceInspector{}
 
-func copyMap(m map[schema.GroupVersionKind]bool) map[schema.GroupVersionKind]bool {
-	cp := make(map[schema.GroupVersionKind]bool)
-	for k, v := range m {
-		cp[k] = v
+// isCoreGroup returns true if group contains core resources in any release
+func isCoreGroup(group string) bool {
+	for gvk, _ := range coreGVKToScope {
+		if group == gvk.Group {
+			return true
+		}
 	}
+	for _, core := range coreReleases {
+		for gvk := range core.gvkToScope {
+			if group == gvk.Group {
+				return true
+			}
+		}
+	}
+
+	return false
+}
 
-	return cp
+// merge adds all discovery information in other, overwriting any existing information
+func (l *LocalResourceInspector) merge(other *LocalResourceInspector) {
+	for k, v := range other.gvkToScope {
+		l.AddGVKToScope(k, v)
+	}
+	for k, v := range other.gvkToPlural {
+		l.AddGVKToPlural(k, v)
+	}
 }
 
-func parseCachedAPIResources() (map[schema.GroupVersionKind]bool, error) {
-	cachedGVKToScope := map[schema.GroupVersionKind]bool{}
+func parseCachedAPIResources(apiResourcesFiles, apiVersionsFiles []string) (*LocalResourceInspector, error) {
+	cached := newLocalResourceInspector(true)
 
-	// TODO: allow paths to be user-specified
-	apiResourcesFile := "api-resources.txt"
-	apiVersionsFile := "api-versions.txt"
+	for _, apiResourcesFile := range apiResourcesFiles {
+		parse := cached.parseAPIResourcesFile
+		if isDocumentFile(apiResourcesFile) {
+			parse = cached.parseAPIResourcesDocumentFile
+		}
+		err := parse(apiResourcesFile)
+		if err != nil {
+			return cached, err
+		}
+	}
 
-	if _, err := os.Stat(apiResourcesFile); os.IsNotExist(err) {
-		return cachedGVKToScope, nil
+	for _, apiVersionsFile := range apiVersionsFiles {
+		parse := cached.parseAPIVersionsFile
+		if isDocumentFile(apiVersionsFile) {
+			parse = cached.parseAPIVersionsDocumentFile
+		}
+		err := parse(apiVersionsFile)
+		if err != nil {
+			return cached, err
+		}
 	}
 
+	return cached, nil
+}
+
+// parseAPIResourcesFile adds each row of `kubectl api-resources` output to discovery
+func (l *LocalResourceInspector) parseAPIResourcesFile(apiResourcesFile string) error {
 	file, err := os.Open(apiResourcesFile)
 	if err != nil {
-		return cachedGVKToScope, err
+		return fmt.Errorf("failed to open API resources file: %w", err)
 	}
 	defer file.Close()
 
+	lineNumber := 0
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
+		lineNumber++
 		line := scanner.Text()
 
 		words := strings.Fields(line)
@@ -94,13 +275,17 @@ func parseCachedAPIResources() (map[schema.GroupVersionKind]bool, error) {
 			continue
 		}
 
+		// Columns are counted from the right since SHORTNAMES may be empty
+		if len(words) < 4 {
+			return fmt.Errorf("%s:%d: expected at least 4 columns but found %d", apiResourcesFile, lineNumber, len(words))
+		}
 		gv, err := schema.ParseGroupVersion(words[len(words)-3])
 		if err != nil {
-			return cachedGVKToScope, err
+			return fmt.Errorf("%s:%d: %w", apiResourcesFile, lineNumber, err)
 		}
 		namespaced, err := strconv.ParseBool(words[len(words)-2])
 		if err != nil {
-			return cachedGVKToScope, err
+			return fmt.Errorf("%s:%d: failed to parse NAMESPACED column: %w", apiResourcesFile, lineNumber, err)
 		}
 		kind := words[len(words)-1]
 
@@ -109,36 +294,58 @@ func parseCachedAPIResources() (map[schema.GroupVersionKind]bool, error) {
 			Version: gv.Version,
 			Kind:    kind,
 		}
-		cachedGVKToScope[gvk] = namespaced
+		l.AddGVKToScope(gvk, namespaced)
+		l.AddGVKToPlural(gvk, words[0])
 	}
-
-	if _, err := os.Stat(apiVersionsFile); os.IsNotExist(err) {
-		return cachedGVKToScope, nil
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read API resources file %s: %w", apiResourcesFile, err)
 	}
 
-	file, err = os.Open(apiVersionsFile)
+	return nil
+}
+
+// parseAPIVersionsFile adds each version listed in `kubectl api-versions` output to the GVKs in
+// discovery belonging to the same group
+func (l *LocalResourceInspector) parseAPIVersionsFile(apiVersionsFile string) error {
+	file, err := os.Open(apiVersionsFile)
 	if err != nil {
-		return cachedGVKToScope, err
+		return fmt.Errorf("failed to open API versions file: %w", err)
 	}
 	defer file.Close()
 
-	scanner = bufio.NewScanner(file)
+	lineNumber := 0
+	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := scanner.Text()
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
 
 		gv, err := schema.ParseGroupVersion(line)
 		if err != nil {
-			return cachedGVKToScope, err
+			return fmt.Errorf("%s:%d: %w", apiVersionsFile, lineNumber, err)
 		}
 
-		for gvk, namespaced := range cachedGVKToScope {
-			if gvk.Group == gv.Group {
-				newGVK := gvk
-				newGVK.Version = gv.Version
-				cachedGVKToScope[newGVK] = namespaced
+		l.addVersion(gv)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read API versions file %s: %w", apiVersionsFile, err)
+	}
+
+	return nil
+}
+
+// addVersion adds the version of gv to each GVK in discovery belonging to the same group
+func (l *LocalResourceInspector) addVersion(gv schema.GroupVersion) {
+	for gvk, namespaced := range l.gvkToScope {
+		if gvk.Group == gv.Group {
+			newGVK := gvk
+			newGVK.Version = gv.Version
+			l.AddGVKToScope(newGVK, namespaced)
+			if plural, ok := l.gvkToPlural[gvk]; ok {
+				l.AddGVKToPlural(newGVK, plural)
 			}
 		}
 	}
-
-	return cachedGVKToScope, nil
 }
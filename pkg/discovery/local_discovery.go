@@ -132,3 +132,134 @@ var coreGVKToScope = map[schema.GroupVersionKind]bool{
 	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                    false,
 	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                                false,
 }
+
+var coreGVKToPlural = map[schema.GroupVersionKind]string{
+	{Group: "", Version: "v1", Kind: "ComponentStatus"}:                                                    "componentstatuses",
+	{Group: "", Version: "v1", Kind: "ConfigMap"}:                                                          "configmaps",
+	{Group: "", Version: "v1", Kind: "Endpoints"}:                                                          "endpoints",
+	{Group: "", Version: "v1", Kind: "Event"}:                                                              "events",
+	{Group: "", Version: "v1", Kind: "LimitRange"}:                                                         "limitranges",
+	{Group: "", Version: "v1", Kind: "Namespace"}:                                                          "namespaces",
+	{Group: "", Version: "v1", Kind: "Node"}:                                                               "nodes",
+	{Group: "", Version: "v1", Kind: "PersistentVolume"}:                                                   "persistentvolumes",
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"}:                                              "persistentvolumeclaims",
+	{Group: "", Version: "v1", Kind: "Pod"}:                                                                "pods",
+	{Group: "", Version: "v1", Kind: "PodTemplate"}:                                                        "podtemplates",
+	{Group: "", Version: "v1", Kind: "ReplicationController"}:                                              "replicationcontrollers",
+	{Group: "", Version: "v1", Kind: "ResourceQuota"}:                                                      "resourcequotas",
+	{Group: "", Version: "v1", Kind: "Secret"}:                                                             "secrets",
+	{Group: "", Version: "v1", Kind: "Service"}:                                                            "services",
+	{Group: "", Version: "v1", Kind: "ServiceAccount"}:                                                     "serviceaccounts",
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}:           "mutatingwebhookconfigurations",
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"}:         "validatingwebhookconfigurations",
+	{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicy"}:        "validatingadmissionpolicies",
+	{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicyBinding"}: "validatingadmissionpolicybindings",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:      "mutatingwebhookconfigurations",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicy"}:         "validatingadmissionpolicies",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicyBinding"}:  "validatingadmissionpolicybindings",
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}:    "validatingwebhookconfigurations",
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}:                       "customresourcedefinitions",
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:                  "customresourcedefinitions",
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"}:                                   "apiservices",
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                              "apiservices",
+	{Group: "apps", Version: "v1", Kind: "ControllerRevision"}:                                             "controllerrevisions",
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"}:                                                      "daemonsets",
+	{Group: "apps", Version: "v1", Kind: "Deployment"}:                                                     "deployments",
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"}:                                                     "replicasets",
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"}:                                                    "statefulsets",
+	{Group: "apps", Version: "v1beta1", Kind: "ControllerRevision"}:                                        "controllerrevisions",
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                                                "deployments",
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                                               "statefulsets",
+	{Group: "apps", Version: "v1beta2", Kind: "ControllerRevision"}:                                        "controllerrevisions",
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                                                 "daemonsets",
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                                                "deployments",
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                                                "replicasets",
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                                               "statefulsets",
+	{Group: "authentication.k8s.io", Version: "v1", Kind: "SelfSubjectReview"}:                             "selfsubjectreviews",
+	{Group: "authentication.k8s.io", Version: "v1", Kind: "TokenReview"}:                                   "tokenreviews",
+	{Group: "authentication.k8s.io", Version: "v1alpha1", Kind: "SelfSubjectReview"}:                       "selfsubjectreviews",
+	{Group: "authentication.k8s.io", Version: "v1beta1", Kind: "SelfSubjectReview"}:                        "selfsubjectreviews",
+	{Group: "authentication.k8s.io", Version: "v1beta1", Kind: "TokenReview"}:                              "tokenreviews",
+	{Group: "authorization.k8s.io", Version: "v1", Kind: "LocalSubjectAccessReview"}:                       "localsubjectaccessreviews",
+	{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectAccessReview"}:                        "selfsubjectaccessreviews",
+	{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectRulesReview"}:                         "selfsubjectrulesreviews",
+	{Group: "authorization.k8s.io", Version: "v1", Kind: "SubjectAccessReview"}:                            "subjectaccessreviews",
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "LocalSubjectAccessReview"}:                  "localsubjectaccessreviews",
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SelfSubjectAccessReview"}:                   "selfsubjectaccessreviews",
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SelfSubjectRulesReview"}:                    "selfsubjectrulesreviews",
+	{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SubjectAccessReview"}:                       "subjectaccessreviews",
+	{Group: "autoscaling", Version: "v1", Kind: "HorizontalPodAutoscaler"}:                                 "horizontalpodautoscalers",
+	{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}:                                 "horizontalpodautoscalers",
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}:                            "horizontalpodautoscalers",
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                            "horizontalpodautoscalers",
+	{Group: "batch", Version: "v1", Kind: "CronJob"}:                                                       "cronjobs",
+	{Group: "batch", Version: "v1", Kind: "Job"}:                                                           "jobs",
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                                  "cronjobs",
+	{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}:                       "certificatesigningrequests",
+	{Group: "certificates.k8s.io", Version: "v1alpha1", Kind: "ClusterTrustBundle"}:                        "clustertrustbundles",
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:                  "certificatesigningrequests",
+	{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease"}:                                           "leases",
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                      "leases",
+	{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}:                                      "endpointslices",
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:                                 "endpointslices",
+	{Group: "events.k8s.io", Version: "v1", Kind: "Event"}:                                                 "events",
+	{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event"}:                                            "events",
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                                           "daemonsets",
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:                                          "deployments",
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                                             "ingresses",
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:                                       "networkpolicies",
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:                                          "replicasets",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                        "flowschemas",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}:        "prioritylevelconfigurations",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                        "flowschemas",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:        "prioritylevelconfigurations",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                        "flowschemas",
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:        "prioritylevelconfigurations",
+	{Group: "imagepolicy.k8s.io", Version: "v1alpha1", Kind: "ImageReview"}:                                "imagereviews",
+	{Group: "internal.apiserver.k8s.io", Version: "v1alpha1", Kind: "StorageVersion"}:                      "storageversions",
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}:                                           "ingresses",
+	{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}:                                      "ingressclasses",
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}:                                     "networkpolicies",
+	{Group: "networking.k8s.io", Version: "v1alpha1", Kind: "ClusterCIDR"}:                                 "clustercidrs",
+	{Group: "networking.k8s.io", Version: "v1alpha1", Kind: "IPAddress"}:                                   "ipaddresses",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                      "ingresses",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                                 "ingressclasses",
+	{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"}:                                            "runtimeclasses",
+	{Group: "node.k8s.io", Version: "v1alpha1", Kind: "RuntimeClass"}:                                      "runtimeclasses",
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:                                       "runtimeclasses",
+	{Group: "policy", Version: "v1", Kind: "Eviction"}:                                                     "evictions",
+	{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}:                                          "poddisruptionbudgets",
+	{Group: "policy", Version: "v1beta1", Kind: "Eviction"}:                                                "evictions",
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                                     "poddisruptionbudgets",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}:                               "clusterroles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}:                        "clusterrolebindings",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}:                                      "roles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}:                               "rolebindings",
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRole"}:                         "clusterroles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRoleBinding"}:                  "clusterrolebindings",
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "Role"}:                                "roles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "RoleBinding"}:                         "rolebindings",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                          "clusterroles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                   "clusterrolebindings",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                                 "roles",
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                          "rolebindings",
+	{Group: "resource.k8s.io", Version: "v1alpha2", Kind: "PodSchedulingContext"}:                          "podschedulingcontexts",
+	{Group: "resource.k8s.io", Version: "v1alpha2", Kind: "ResourceClaim"}:                                 "resourceclaims",
+	{Group: "resource.k8s.io", Version: "v1alpha2", Kind: "ResourceClaimTemplate"}:                         "resourceclaimtemplates",
+	{Group: "resource.k8s.io", Version: "v1alpha2", Kind: "ResourceClass"}:                                 "resourceclasses",
+	{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}:                                     "priorityclasses",
+	{Group: "scheduling.k8s.io", Version: "v1alpha1", Kind: "PriorityClass"}:                               "priorityclasses",
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                                "priorityclasses",
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"}:                                            "csidrivers",
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}:                                              "csinodes",
+	{Group: "storage.k8s.io", Version: "v1", Kind: "CSIStorageCapacity"}:                                   "csistoragecapacities",
+	{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}:                                         "storageclasses",
+	{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment"}:                                     "volumeattachments",
+	{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "CSIStorageCapacity"}:                             "csistoragecapacities",
+	{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "VolumeAttachment"}:                               "volumeattachments",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                       "csidrivers",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                         "csinodes",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                              "csistoragecapacities",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                    "storageclasses",
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                                "volumeattachments",
+}
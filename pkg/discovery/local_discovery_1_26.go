@@ -0,0 +1,220 @@
+package discovery
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	coreReleases["1.26"] = coreRelease{
+		gvkToScope: map[schema.GroupVersionKind]bool{
+			{Group: "", Version: "v1", Kind: "ComponentStatus"}:                                                    false,
+			{Group: "", Version: "v1", Kind: "ConfigMap"}:                                                          true,
+			{Group: "", Version: "v1", Kind: "Endpoints"}:                                                          true,
+			{Group: "", Version: "v1", Kind: "Event"}:                                                              true,
+			{Group: "", Version: "v1", Kind: "LimitRange"}:                                                         true,
+			{Group: "", Version: "v1", Kind: "Namespace"}:                                                          false,
+			{Group: "", Version: "v1", Kind: "Node"}:                                                               false,
+			{Group: "", Version: "v1", Kind: "PersistentVolume"}:                                                   false,
+			{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"}:                                              true,
+			{Group: "", Version: "v1", Kind: "Pod"}:                                                                true,
+			{Group: "", Version: "v1", Kind: "PodTemplate"}:                                                        true,
+			{Group: "", Version: "v1", Kind: "ReplicationController"}:                                              true,
+			{Group: "", Version: "v1", Kind: "ResourceQuota"}:                                                      true,
+			{Group: "", Version: "v1", Kind: "Secret"}:                                                             true,
+			{Group: "", Version: "v1", Kind: "Service"}:                                                            true,
+			{Group: "", Version: "v1", Kind: "ServiceAccount"}:                                                     true,
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}:           false,
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"}:         false,
+			{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicy"}:        false,
+			{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicyBinding"}: false,
+			{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}:                       false,
+			{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"}:                                   false,
+			{Group: "apps", Version: "v1", Kind: "ControllerRevision"}:                                             true,
+			{Group: "apps", Version: "v1", Kind: "DaemonSet"}:                                                      true,
+			{Group: "apps", Version: "v1", Kind: "Deployment"}:                                                     true,
+			{Group: "apps", Version: "v1", Kind: "ReplicaSet"}:                                                     true,
+			{Group: "apps", Version: "v1", Kind: "StatefulSet"}:                                                    true,
+			{Group: "authentication.k8s.io", Version: "v1", Kind: "TokenReview"}:                                   false,
+			{Group: "authentication.k8s.io", Version: "v1alpha1", Kind: "SelfSubjectReview"}:                       false,
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "LocalSubjectAccessReview"}:                       true,
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectAccessReview"}:                        false,
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectRulesReview"}:                         false,
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SubjectAccessReview"}:                            false,
+			{Group: "autoscaling", Version: "v1", Kind: "HorizontalPodAutoscaler"}:                                 true,
+			{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}:                                 true,
+			{Group: "batch", Version: "v1", Kind: "CronJob"}:                                                       true,
+			{Group: "batch", Version: "v1", Kind: "Job"}:                                                           true,
+			{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}:                       false,
+			{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease"}:                                           true,
+			{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}:                                      true,
+			{Group: "events.k8s.io", Version: "v1", Kind: "Event"}:                                                 true,
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                        false,
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:        false,
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                        false,
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:        false,
+			{Group: "imagepolicy.k8s.io", Version: "v1alpha1", Kind: "ImageReview"}:                                false,
+			{Group: "internal.apiserver.k8s.io", Version: "v1alpha1", Kind: "StorageVersion"}:                      false,
+			{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}:                                           true,
+			{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}:                                      false,
+			{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}:                                     true,
+			{Group: "networking.k8s.io", Version: "v1alpha1", Kind: "ClusterCIDR"}:                                 false,
+			{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"}:                                            false,
+			{Group: "policy", Version: "v1", Kind: "Eviction"}:                                                     true,
+			{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}:                                          true,
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}:                               false,
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}:                        false,
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}:                                      true,
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}:                               true,
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "PodScheduling"}:                                 true,
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClaim"}:                                 true,
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClaimTemplate"}:                         true,
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClass"}:                                 false,
+			{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}:                                     false,
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"}:                                            false,
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}:                                              false,
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSIStorageCapacity"}:                                   true,
+			{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}:                                         false,
+			{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment"}:                                     false,
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "CSIStorageCapacity"}:                             true,
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "VolumeAttachment"}:                               false,
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                              true,
+		},
+
+		gvkToPlural: map[schema.GroupVersionKind]string{
+			{Group: "", Version: "v1", Kind: "ComponentStatus"}:                                                    "componentstatuses",
+			{Group: "", Version: "v1", Kind: "ConfigMap"}:                                                          "configmaps",
+			{Group: "", Version: "v1", Kind: "Endpoints"}:                                                          "endpoints",
+			{Group: "", Version: "v1", Kind: "Event"}:                                                              "events",
+			{Group: "", Version: "v1", Kind: "LimitRange"}:                                                         "limitranges",
+			{Group: "", Version: "v1", Kind: "Namespace"}:                                                          "namespaces",
+			{Group: "", Version: "v1", Kind: "Node"}:                                                               "nodes",
+			{Group: "", Version: "v1", Kind: "PersistentVolume"}:                                                   "persistentvolumes",
+			{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"}:                                              "persistentvolumeclaims",
+			{Group: "", Version: "v1", Kind: "Pod"}:                                                                "pods",
+			{Group: "", Version: "v1", Kind: "PodTemplate"}:                                                        "podtemplates",
+			{Group: "", Version: "v1", Kind: "ReplicationController"}:                                              "replicationcontrollers",
+			{Group: "", Version: "v1", Kind: "ResourceQuota"}:                                                      "resourcequotas",
+			{Group: "", Version: "v1", Kind: "Secret"}:                                                             "secrets",
+			{Group: "", Version: "v1", Kind: "Service"}:                                                            "services",
+			{Group: "", Version: "v1", Kind: "ServiceAccount"}:                                                     "serviceaccounts",
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}:           "mutatingwebhookconfigurations",
+			{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"}:         "validatingwebhookconfigurations",
+			{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicy"}:        "validatingadmissionpolicies",
+			{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "ValidatingAdmissionPolicyBinding"}: "validatingadmissionpolicybindings",
+			{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}:                       "customresourcedefinitions",
+			{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"}:                                   "apiservices",
+			{Group: "apps", Version: "v1", Kind: "ControllerRevision"}:                                             "controllerrevisions",
+			{Group: "apps", Version: "v1", Kind: "DaemonSet"}:                                                      "daemonsets",
+			{Group: "apps", Version: "v1", Kind: "Deployment"}:                                                     "deployments",
+			{Group: "apps", Version: "v1", Kind: "ReplicaSet"}:                                                     "replicasets",
+			{Group: "apps", Version: "v1", Kind: "StatefulSet"}:                                                    "statefulsets",
+			{Group: "authentication.k8s.io", Version: "v1", Kind: "TokenReview"}:                                   "tokenreviews",
+			{Group: "authentication.k8s.io", Version: "v1alpha1", Kind: "SelfSubjectReview"}:                       "selfsubjectreviews",
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "LocalSubjectAccessReview"}:                       "localsubjectaccessreviews",
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectAccessReview"}:                        "selfsubjectaccessreviews",
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectRulesReview"}:                         "selfsubjectrulesreviews",
+			{Group: "authorization.k8s.io", Version: "v1", Kind: "SubjectAccessReview"}:                            "subjectaccessreviews",
+			{Group: "autoscaling", Version: "v1", Kind: "HorizontalPodAutoscaler"}:                                 "horizontalpodautoscalers",
+			{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}:                                 "horizontalpodautoscalers",
+			{Group: "batch", Version: "v1", Kind: "CronJob"}:                                                       "cronjobs",
+			{Group: "batch", Version: "v1", Kind: "Job"}:                                                           "jobs",
+			{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}:                       "certificatesigningrequests",
+			{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease"}:                                           "leases",
+			{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}:                                      "endpointslices",
+			{Group: "events.k8s.io", Version: "v1", Kind: "Event"}:                                                 "events",
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                        "flowschemas",
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:        "prioritylevelconfigurations",
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                        "flowschemas",
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:        "prioritylevelconfigurations",
+			{Group: "imagepolicy.k8s.io", Version: "v1alpha1", Kind: "ImageReview"}:                                "imagereviews",
+			{Group: "internal.apiserver.k8s.io", Version: "v1alpha1", Kind: "StorageVersion"}:                      "storageversions",
+			{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}:                                           "ingresses",
+			{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}:                                      "ingressclasses",
+			{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}:                                     "networkpolicies",
+			{Group: "networking.k8s.io", Version: "v1alpha1", Kind: "ClusterCIDR"}:                                 "clustercidrs",
+			{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"}:                                            "runtimeclasses",
+			{Group: "policy", Version: "v1", Kind: "Eviction"}:                                                     "evictions",
+			{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}:                                          "poddisruptionbudgets",
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}:                               "clusterroles",
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}:                        "clusterrolebindings",
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}:                                      "roles",
+			{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}:                               "rolebindings",
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "PodScheduling"}:                                 "podschedulings",
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClaim"}:                                 "resourceclaims",
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClaimTemplate"}:                         "resourceclaimtemplates",
+			{Group: "resource.k8s.io", Version: "v1alpha1", Kind: "ResourceClass"}:                                 "resourceclasses",
+			{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}:                                     "priorityclasses",
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"}:                                            "csidrivers",
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}:                                              "csinodes",
+			{Group: "storage.k8s.io", Version: "v1", Kind: "CSIStorageCapacity"}:                                   "csistoragecapacities",
+			{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}:                                         "storageclasses",
+			{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment"}:                                     "volumeattachments",
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "CSIStorageCapacity"}:                             "csistoragecapacities",
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "VolumeAttachment"}:                               "volumeattachments",
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                              "csistoragecapacities",
+		},
+
+		lifecycles: map[schema.GroupVersionKind]APILifecycle{
+			{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:   {Deprecated: "1.16", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}},
+			{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}: {Deprecated: "1.16", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"}},
+			{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:               {Deprecated: "1.16", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}},
+			{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                           {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"}},
+			{Group: "apps", Version: "v1beta1", Kind: "ControllerRevision"}:                                     {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ControllerRevision"}},
+			{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                                             {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+			{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                                            {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}},
+			{Group: "apps", Version: "v1beta2", Kind: "ControllerRevision"}:                                     {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ControllerRevision"}},
+			{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                                              {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}},
+			{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                                             {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+			{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                                             {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}},
+			{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                                            {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}},
+			{Group: "authentication.k8s.io", Version: "v1beta1", Kind: "TokenReview"}:                           {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "authentication.k8s.io", Version: "v1", Kind: "TokenReview"}},
+			{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "LocalSubjectAccessReview"}:               {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "authorization.k8s.io", Version: "v1", Kind: "LocalSubjectAccessReview"}},
+			{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SelfSubjectAccessReview"}:                {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectAccessReview"}},
+			{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SelfSubjectRulesReview"}:                 {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "authorization.k8s.io", Version: "v1", Kind: "SelfSubjectRulesReview"}},
+			{Group: "authorization.k8s.io", Version: "v1beta1", Kind: "SubjectAccessReview"}:                    {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "authorization.k8s.io", Version: "v1", Kind: "SubjectAccessReview"}},
+			{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}:                         {Deprecated: "1.22", Removed: "1.25", Replacement: schema.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}},
+			{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                         {Deprecated: "1.23", Removed: "1.26", Replacement: schema.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}},
+			{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                               {Deprecated: "1.21", Removed: "1.25", Replacement: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}},
+			{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:               {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "certificates.k8s.io", Version: "v1", Kind: "CertificateSigningRequest"}},
+			{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                   {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease"}},
+			{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:                              {Deprecated: "1.21", Removed: "1.25", Replacement: schema.GroupVersionKind{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}},
+			{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event"}:                                         {Deprecated: "1.22", Removed: "1.25"},
+			{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                                        {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}},
+			{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:                                       {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+			{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                                          {Deprecated: "1.14", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}},
+			{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:                                    {Deprecated: "1.9", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}},
+			{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                                {Deprecated: "1.11", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}},
+			{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:                                       {Deprecated: "1.8", Removed: "1.16", Replacement: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1alpha1", Kind: "FlowSchema"}:                    {Deprecated: "1.20", Removed: "1.21", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1alpha1", Kind: "PriorityLevelConfiguration"}:    {Deprecated: "1.20", Removed: "1.21", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                     {Deprecated: "1.23", Removed: "1.26", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}:     {Deprecated: "1.23", Removed: "1.26", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                     {Deprecated: "1.26", Removed: "1.29", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:     {Deprecated: "1.26", Removed: "1.29", Replacement: schema.GroupVersionKind{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                     {Deprecated: "1.29", Removed: "1.32"},
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:     {Deprecated: "1.29", Removed: "1.32"},
+			{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                   {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}},
+			{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                              {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}},
+			{Group: "node.k8s.io", Version: "v1alpha1", Kind: "RuntimeClass"}:                                   {Deprecated: "1.20", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"}},
+			{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:                                    {Deprecated: "1.22", Removed: "1.25"},
+			{Group: "policy", Version: "v1beta1", Kind: "Eviction"}:                                             {Deprecated: "1.22", Removed: "1.25"},
+			{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                                  {Deprecated: "1.21", Removed: "1.25", Replacement: schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}},
+			{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                                    {Deprecated: "1.21", Removed: "1.25"},
+			{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRole"}:                      {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "ClusterRoleBinding"}:               {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "Role"}:                             {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "RoleBinding"}:                      {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                       {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                              {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"}},
+			{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                       {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"}},
+			{Group: "scheduling.k8s.io", Version: "v1alpha1", Kind: "PriorityClass"}:                            {Deprecated: "1.14", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}},
+			{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                             {Deprecated: "1.14", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}},
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "CSIStorageCapacity"}:                          {Deprecated: "1.21", Removed: "", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}},
+			{Group: "storage.k8s.io", Version: "v1alpha1", Kind: "VolumeAttachment"}:                            {Deprecated: "1.21", Removed: "", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment"}},
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                    {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"}},
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                      {Deprecated: "1.17", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}},
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                           {Deprecated: "1.24", Removed: "1.27", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSIStorageCapacity"}},
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                 {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}},
+			{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                             {Deprecated: "1.19", Removed: "1.22", Replacement: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "VolumeAttachment"}},
+		},
+	}
+}
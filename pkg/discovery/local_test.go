@@ -0,0 +1,365 @@
+package discovery
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+// writeFile writes contents to a file called name in dir and returns its path
+func writeFile(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(contents), 0644)
+	require.Nil(t, err)
+	return path
+}
+
+func TestCachedAPIResources(t *testing.T) {
+	dir := t.TempDir()
+
+	apiResourcesFile := writeFile(t, dir, "api-resources.txt", `
+NAME         SHORTNAMES   APIVERSION     NAMESPACED   KIND
+testers      tst          test.io/v1     true         Tester
+`)
+	overrideAPIResourcesFile := writeFile(t, dir, "override-api-resources.txt", `
+NAME         SHORTNAMES   APIVERSION     NAMESPACED   KIND
+testers                   test.io/v1     false        Tester
+`)
+	apiVersionsFile := writeFile(t, dir, "api-versions.txt", `
+test.io/v1
+test.io/v2
+`)
+
+	// Ensure resources are discovered across versions
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{apiResourcesFile}, APIVersionsFiles: []string{apiVersionsFile}})
+	require.Nil(t, err)
+	for _, version := range []string{"v1", "v2"} {
+		namespaced, err := l.IsNamespaced(schema.GroupVersionKind{Group: "test.io", Version: version, Kind: "Tester"})
+		require.Nil(t, err)
+		require.True(t, namespaced)
+	}
+
+	// Ensure plurals are discovered
+	require.Equal(t, "testers", l.Plural(schema.GroupVersionKind{Group: "test.io", Version: "v2", Kind: "Tester"}))
+	require.Equal(t, "endpoints", l.Plural(schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Endpoints"}))
+	require.Equal(t, "examples", l.Plural(schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Example"}))
+
+	// Ensure later files take precedence
+	l, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{apiResourcesFile, overrideAPIResourcesFile}})
+	require.Nil(t, err)
+	namespaced, err := l.IsNamespaced(schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Tester"})
+	require.Nil(t, err)
+	require.False(t, namespaced)
+
+	// Ensure parsing errors name the file and line
+	invalidAPIResourcesFile := writeFile(t, dir, "invalid-api-resources.txt", `NAME         SHORTNAMES   APIVERSION     NAMESPACED   KIND
+testers      tst          test.io/v1     maybe        Tester
+`)
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{invalidAPIResourcesFile}})
+	require.EqualError(t, err, invalidAPIResourcesFile+`:2: failed to parse NAMESPACED column: strconv.ParseBool: parsing "maybe": invalid syntax`)
+
+	invalidAPIVersionsFile := writeFile(t, dir, "invalid-api-versions.txt", "test.io/v1/v2\n")
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIVersionsFiles: []string{invalidAPIVersionsFile}})
+	require.EqualError(t, err, invalidAPIVersionsFile+`:1: unexpected GroupVersion string: test.io/v1/v2`)
+
+	// Ensure missing files are reported
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{filepath.Join(dir, "missing.txt")}})
+	require.NotNil(t, err)
+}
+
+func TestCachedAPIResourceDocuments(t *testing.T) {
+	dir := t.TempDir()
+
+	// Dump of /api/v1 and /apis/test.io/v1
+	apiResourceListFile := writeFile(t, dir, "api-resources.yaml", `
+kind: APIResourceList
+apiVersion: v1
+groupVersion: v1
+resources:
+- name: pods
+  singularName: pod
+  namespaced: true
+  kind: Pod
+  verbs: [get, list]
+  shortNames: [po]
+- name: pods/status
+  singularName: ""
+  namespaced: true
+  kind: Pod
+  verbs: [get]
+---
+kind: APIResourceList
+apiVersion: v1
+groupVersion: test.io/v1
+resources:
+- name: testers
+  singularName: tester
+  namespaced: false
+  kind: Tester
+  verbs: [get, list]
+`)
+	// Aggregated discovery dump of /apis
+	apiGroupDiscoveryListFile := writeFile(t, dir, "api-resources.json", `{
+  "kind": "APIGroupDiscoveryList",
+  "apiVersion": "apidiscovery.k8s.io/v2",
+  "items": [
+    {
+      "metadata": {"name": "example.io"},
+      "versions": [
+        {
+          "version": "v1beta1",
+          "resources": [
+            {
+              "resource": "examples",
+              "responseKind": {"group": "", "version": "", "kind": "Example"},
+              "scope": "Namespaced",
+              "singularResource": "example",
+              "verbs": ["get", "list"],
+              "shortNames": ["ex"]
+            }
+          ],
+          "freshness": "Current"
+        }
+      ]
+    }
+  ]
+}`)
+	// Dump of /apis
+	apiGroupListFile := writeFile(t, dir, "api-versions.json", `{
+  "kind": "APIGroupList",
+  "apiVersion": "v1",
+  "groups": [
+    {
+      "name": "test.io",
+      "versions": [
+        {"groupVersion": "test.io/v1", "version": "v1"},
+        {"groupVersion": "test.io/v2", "version": "v2"}
+      ]
+    }
+  ]
+}`)
+
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{apiResourceListFile, apiGroupDiscoveryListFile}, APIVersionsFiles: []string{apiGroupListFile}})
+	require.Nil(t, err)
+
+	for gvk, expectedNamespaced := range map[schema.GroupVersionKind]bool{
+		{Group: "", Version: "v1", Kind: "Pod"}:                    true,
+		{Group: "test.io", Version: "v1", Kind: "Tester"}:          false,
+		{Group: "test.io", Version: "v2", Kind: "Tester"}:          false,
+		{Group: "example.io", Version: "v1beta1", Kind: "Example"}: true,
+	} {
+		namespaced, err := l.IsNamespaced(gvk)
+		require.Nil(t, err)
+		require.Equal(t, expectedNamespaced, namespaced, gvk.String())
+	}
+
+	// Ensure unsupported documents are reported
+	unsupportedFile := writeFile(t, dir, "unsupported.yaml", `
+apiVersion: v1
+kind: ConfigMap
+`)
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{unsupportedFile}})
+	require.EqualError(t, err, unsupportedFile+`: document 1: unsupported kind "ConfigMap", expected DiscoverySnapshot, APIResourceList, APIGroupDiscoveryList or an OpenAPI document`)
+}
+
+func TestOpenAPIDocuments(t *testing.T) {
+	dir := t.TempDir()
+
+	// Dump of /openapi/v2
+	openAPIV2File := writeFile(t, dir, "openapi-v2.json", `{
+  "swagger": "2.0",
+  "paths": {
+    "/api/v1/namespaces": {
+      "get": {"x-kubernetes-action": "list", "x-kubernetes-group-version-kind": {"group": "", "version": "v1", "kind": "Namespace"}}
+    },
+    "/api/v1/namespaces/{name}": {
+      "parameters": [{"name": "name", "in": "path"}],
+      "get": {"x-kubernetes-action": "get", "x-kubernetes-group-version-kind": {"group": "", "version": "v1", "kind": "Namespace"}}
+    },
+    "/api/v1/pods": {
+      "get": {"x-kubernetes-action": "list", "x-kubernetes-group-version-kind": {"group": "", "version": "v1", "kind": "Pod"}}
+    },
+    "/api/v1/namespaces/{namespace}/pods/{name}": {
+      "get": {"x-kubernetes-action": "get", "x-kubernetes-group-version-kind": {"group": "", "version": "v1", "kind": "Pod"}}
+    },
+    "/api/v1/watch/namespaces/{namespace}/pods": {
+      "get": {"x-kubernetes-action": "watchlist", "x-kubernetes-group-version-kind": {"group": "", "version": "v1", "kind": "Pod"}}
+    },
+    "/apis/apps/v1/namespaces/{namespace}/deployments/{name}/scale": {
+      "get": {"x-kubernetes-action": "get", "x-kubernetes-group-version-kind": {"group": "autoscaling", "version": "v1", "kind": "Scale"}}
+    },
+    "/apis/test.io/v1/testers": {
+      "post": {"x-kubernetes-action": "post", "x-kubernetes-group-version-kind": {"group": "test.io", "version": "v1", "kind": "Tester"}}
+    }
+  },
+  "definitions": {
+    "io.test.v1.Tester": {
+      "x-kubernetes-group-version-kind": [{"group": "test.io", "version": "v1", "kind": "Tester"}]
+    }
+  }
+}`)
+	// Dump of /openapi/v3/apis/example.io/v1beta1
+	openAPIV3File := writeFile(t, dir, "openapi-v3.yaml", `
+openapi: 3.0.0
+paths:
+  /apis/example.io/v1beta1/namespaces/{namespace}/examples:
+    get:
+      x-kubernetes-action: list
+      x-kubernetes-group-version-kind:
+        group: example.io
+        version: v1beta1
+        kind: Example
+`)
+
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{openAPIV2File, openAPIV3File}, ExcludeCore: true, Strict: true})
+	require.Nil(t, err)
+
+	for gvk, expectedNamespaced := range map[schema.GroupVersionKind]bool{
+		{Group: "", Version: "v1", Kind: "Namespace"}:              false,
+		{Group: "", Version: "v1", Kind: "Pod"}:                    true,
+		{Group: "test.io", Version: "v1", Kind: "Tester"}:          false,
+		{Group: "example.io", Version: "v1beta1", Kind: "Example"}: true,
+	} {
+		namespaced, err := l.IsNamespaced(gvk)
+		require.Nil(t, err)
+		require.Equal(t, expectedNamespaced, namespaced, gvk.String())
+	}
+	require.Equal(t, l.Plural(schema.GroupVersionKind{Group: "example.io", Version: "v1beta1", Kind: "Example"}), "examples")
+
+	// Ensure subresources are ignored
+	_, err = l.IsNamespaced(schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"})
+	require.EqualError(t, err, "could not find REST mapping for resource autoscaling/v1, Kind=Scale")
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	client := &fake.FakeDiscovery{
+		Fake: &kubetesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "test.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "testers", Namespaced: true, Kind: "Tester"},
+						{Name: "testers/status", Namespaced: true, Kind: "Tester"},
+					},
+				},
+			},
+		},
+	}
+
+	// Write snapshot to disk
+	snapshot, err := NewSnapshot(client)
+	require.Nil(t, err)
+	var b bytes.Buffer
+	err = snapshot.Write(&b)
+	require.Nil(t, err)
+	snapshotFile := writeFile(t, dir, "snapshot.yaml", b.String())
+
+	// Ensure snapshot can be loaded
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{snapshotFile}})
+	require.Nil(t, err)
+	namespaced, err := l.IsNamespaced(schema.GroupVersionKind{Group: "test.io", Version: "v1", Kind: "Tester"})
+	require.Nil(t, err)
+	require.True(t, namespaced)
+
+	// Ensure unknown snapshot versions are rejected
+	unsupportedSnapshotFile := writeFile(t, dir, "unsupported-snapshot.yaml", `
+apiVersion: kfmt.dev/v1
+kind: DiscoverySnapshot
+`)
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{APIResourcesFiles: []string{unsupportedSnapshotFile}})
+	require.EqualError(t, err, unsupportedSnapshotFile+`: document 1: unsupported DiscoverySnapshot version "kfmt.dev/v1", expected kfmt.dev/v1alpha1`)
+}
+
+func TestGroupKindFallback(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1beta3", Kind: "Deployment"}
+
+	// Ensure unseen versions of known kinds are discovered
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{})
+	require.Nil(t, err)
+	namespaced, err := l.IsNamespaced(gvk)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	require.Equal(t, "deployments", l.Plural(gvk))
+
+	// Ensure unknown kinds are still rejected
+	_, err = l.IsNamespaced(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Tester"})
+	require.EqualError(t, err, "could not find REST mapping for resource apps/v1, Kind=Tester")
+
+	// Ensure strict mode requires an exact match
+	l, err = NewLocalResourceInspector(LocalResourceInspectorOptions{Strict: true})
+	require.Nil(t, err)
+	_, err = l.IsNamespaced(gvk)
+	require.EqualError(t, err, "could not find REST mapping for resource apps/v1beta3, Kind=Deployment")
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	cronJob := schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}
+	validatingAdmissionPolicy := schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingAdmissionPolicy"}
+	role := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1alpha1", Kind: "Role"}
+
+	// Ensure kinds removed in a later release are discovered
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{KubernetesVersion: "1.24", Strict: true})
+	require.Nil(t, err)
+	namespaced, err := l.IsNamespaced(cronJob)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	_, err = l.IsNamespaced(validatingAdmissionPolicy)
+	require.EqualError(t, err, "could not find REST mapping for resource admissionregistration.k8s.io/v1, Kind=ValidatingAdmissionPolicy")
+
+	// Ensure unmarked alpha kinds removed before any supported release are not discovered
+	_, err = l.IsNamespaced(role)
+	require.EqualError(t, err, "could not find REST mapping for resource rbac.authorization.k8s.io/v1alpha1, Kind=Role")
+
+	// Ensure kinds removed in the release are not discovered and kinds added are
+	l, err = NewLocalResourceInspector(LocalResourceInspectorOptions{KubernetesVersion: "v1.30.2", Strict: true})
+	require.Nil(t, err)
+	_, err = l.IsNamespaced(cronJob)
+	require.EqualError(t, err, "could not find REST mapping for resource batch/v1beta1, Kind=CronJob")
+
+	// Ensure removed kinds are not resolved through other versions without strict discovery
+	l, err = NewLocalResourceInspector(LocalResourceInspectorOptions{KubernetesVersion: "v1.30.2"})
+	require.Nil(t, err)
+	_, err = l.IsNamespaced(cronJob)
+	require.EqualError(t, err, "could not find REST mapping for resource batch/v1beta1, Kind=CronJob")
+	require.Equal(t, l.Plural(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}), "cronjobs")
+	namespaced, err = l.IsNamespaced(validatingAdmissionPolicy)
+	require.Nil(t, err)
+	require.False(t, namespaced)
+
+	// Ensure unsupported releases are rejected
+	_, err = NewLocalResourceInspector(LocalResourceInspectorOptions{KubernetesVersion: "1.10"})
+	require.EqualError(t, err, "unsupported Kubernetes version 1.10, supported versions are 1.24, 1.25, 1.26, 1.27, 1.28, 1.29, 1.30, 1.31")
+}
+
+func TestCatalog(t *testing.T) {
+	certificate := schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+	clusterIssuer := schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "ClusterIssuer"}
+
+	// Ensure well-known CRDs are not discovered unless the catalog is included
+	l, err := NewLocalResourceInspector(LocalResourceInspectorOptions{ExcludeCore: true})
+	require.Nil(t, err)
+	_, err = l.IsNamespaced(certificate)
+	require.EqualError(t, err, "could not find REST mapping for resource cert-manager.io/v1, Kind=Certificate")
+
+	l, err = NewLocalResourceInspector(LocalResourceInspectorOptions{ExcludeCore: true, Catalog: true})
+	require.Nil(t, err)
+	namespaced, err := l.IsNamespaced(certificate)
+	require.Nil(t, err)
+	require.True(t, namespaced)
+	namespaced, err = l.IsNamespaced(clusterIssuer)
+	require.Nil(t, err)
+	require.False(t, namespaced)
+	require.Equal(t, l.Plural(clusterIssuer), "clusterissuers")
+
+	// Ensure catalog groups are not treated as core groups
+	require.False(t, l.IsCoreGroup(certificate.Group))
+}
@@ -0,0 +1,124 @@
+package discovery
+
+import (
+	"encoding/json"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// openAPIGVKExtension is set on operations and definitions to the GVKs they correspond to
+	openAPIGVKExtension = "x-kubernetes-group-version-kind"
+	// openAPINamespaceParameter is the path template parameter of namespaced resources
+	openAPINamespaceParameter = "{namespace}"
+)
+
+// openAPIMethods are the path item fields containing operations
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// openAPIDocument contains the fields of an OpenAPI v2 document served at /openapi/v2 or an OpenAPI
+// v3 document served at /openapi/v3/<prefix>/<group>/<version> required for discovery
+type openAPIDocument struct {
+	Swagger string                                `json:"swagger"`
+	OpenAPI string                                `json:"openapi"`
+	Paths   map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// openAPIOperation contains the GVK an operation acts on
+type openAPIOperation struct {
+	GVK *metav1.GroupVersionKind `json:"x-kubernetes-group-version-kind"`
+}
+
+// isOpenAPIDocument returns true if document is an OpenAPI v2 or v3 document
+func isOpenAPIDocument(document json.RawMessage) bool {
+	var openAPIDocument openAPIDocument
+	if err := json.Unmarshal(document, &openAPIDocument); err != nil {
+		return false
+	}
+	return openAPIDocument.Swagger != "" || openAPIDocument.OpenAPI != ""
+}
+
+// addOpenAPIDocument adds the resources served at the paths of an OpenAPI document to discovery.
+// Resources are identified by the x-kubernetes-group-version-kind extension of their operations and
+// are namespaced if any of their paths contain the {namespace} parameter
+func (l *LocalResourceInspector) addOpenAPIDocument(document json.RawMessage) error {
+	var openAPIDocument openAPIDocument
+	if err := json.Unmarshal(document, &openAPIDocument); err != nil {
+		return err
+	}
+
+	gvkToScope := map[schema.GroupVersionKind]bool{}
+	gvkToPlural := map[schema.GroupVersionKind]string{}
+	for path, pathItem := range openAPIDocument.Paths {
+		plural, namespaced, ok := parseOpenAPIPath(path)
+		if !ok {
+			continue
+		}
+
+		for _, method := range openAPIMethods {
+			rawOperation, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+			var operation openAPIOperation
+			if err := json.Unmarshal(rawOperation, &operation); err != nil {
+				return err
+			}
+			if operation.GVK == nil || operation.GVK.Kind == "" {
+				continue
+			}
+
+			gvk := schema.GroupVersionKind{
+				Group:   operation.GVK.Group,
+				Version: operation.GVK.Version,
+				Kind:    operation.GVK.Kind,
+			}
+			// Namespaced resources can also be listed across all Namespaces
+			gvkToScope[gvk] = gvkToScope[gvk] || namespaced
+			gvkToPlural[gvk] = plural
+		}
+	}
+
+	for gvk, namespaced := range gvkToScope {
+		l.AddGVKToScope(gvk, namespaced)
+		l.AddGVKToPlural(gvk, gvkToPlural[gvk])
+	}
+
+	return nil
+}
+
+// parseOpenAPIPath returns the plural of the resource served at path and whether path contains the
+// {namespace} parameter. Paths that do not serve a resource, such as subresource and deprecated
+// watch paths, are not ok
+func parseOpenAPIPath(path string) (string, bool, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) > 2 && segments[0] == "api":
+		// /api/<version>/...
+		segments = segments[2:]
+	case len(segments) > 3 && segments[0] == "apis":
+		// /apis/<group>/<version>/...
+		segments = segments[3:]
+	default:
+		return "", false, false
+	}
+
+	if segments[0] == "watch" {
+		return "", false, false
+	}
+
+	namespaced := false
+	if len(segments) > 2 && segments[0] == "namespaces" && segments[1] == openAPINamespaceParameter {
+		namespaced = true
+		segments = segments[2:]
+	}
+
+	// Only the collection and item paths of a resource are considered
+	if len(segments) > 2 || strings.HasPrefix(segments[0], "{") {
+		return "", false, false
+	}
+
+	return segments[0], namespaced, true
+}
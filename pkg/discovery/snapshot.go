@@ -0,0 +1,317 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	kdiscov "k8s.io/client-go/discovery"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SnapshotAPIVersion is the version of the snapshot format written by Snapshot.Write
+	SnapshotAPIVersion = "kfmt.dev/v1alpha1"
+	// SnapshotKind is the kind of the document written by Snapshot.Write
+	SnapshotKind = "DiscoverySnapshot"
+
+	apiResourceListKind       = "APIResourceList"
+	apiGroupListKind          = "APIGroupList"
+	apiGroupDiscoveryListKind = "APIGroupDiscoveryList"
+
+	namespacedScope = "Namespaced"
+)
+
+// Snapshot is an offline copy of the discovery information served by an API Server that can be
+// loaded by LocalResourceInspector
+type Snapshot struct {
+	metav1.TypeMeta `json:",inline"`
+	// ServerVersion is the version of the API Server the snapshot was taken from
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// Resources contains the resources served for each group version
+	Resources []metav1.APIResourceList `json:"resources"`
+}
+
+// NewSnapshot retrieves all discovery information from client. Group versions are sorted by group
+// and then version and resources are sorted by name so that snapshots can be compared. If some group
+// versions cannot be discovered (e.g. because an aggregated API Server is unavailable) the partial
+// snapshot is returned along with an error for which kdiscov.IsGroupDiscoveryFailedError is true
+func NewSnapshot(client kdiscov.DiscoveryInterface) (*Snapshot, error) {
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve server version: %w", err)
+	}
+
+	_, apiResourceLists, err := client.ServerGroupsAndResources()
+	if err != nil && !kdiscov.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to retrieve server resources: %w", err)
+	}
+
+	snapshot := newSnapshot(apiResourceLists)
+	snapshot.ServerVersion = serverVersion.GitVersion
+
+	return snapshot, err
+}
+
+// newSnapshot returns a sorted snapshot of apiResourceLists
+func newSnapshot(apiResourceLists []*metav1.APIResourceList) *Snapshot {
+	snapshot := &Snapshot{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SnapshotAPIVersion,
+			Kind:       SnapshotKind,
+		},
+	}
+	for _, apiResourceList := range apiResourceLists {
+		apiResourceListCopy := apiResourceList.DeepCopy()
+		// Omit type information from each list since it is implied by the snapshot
+		apiResourceListCopy.TypeMeta = metav1.TypeMeta{}
+		sort.Slice(apiResourceListCopy.APIResources, func(i, j int) bool {
+			return apiResourceListCopy.APIResources[i].Name < apiResourceListCopy.APIResources[j].Name
+		})
+		snapshot.Resources = append(snapshot.Resources, *apiResourceListCopy)
+	}
+	sort.Slice(snapshot.Resources, func(i, j int) bool {
+		gvi, _ := schema.ParseGroupVersion(snapshot.Resources[i].GroupVersion)
+		gvj, _ := schema.ParseGroupVersion(snapshot.Resources[j].GroupVersion)
+		if gvi.Group != gvj.Group {
+			return gvi.Group < gvj.Group
+		}
+		return gvi.Version < gvj.Version
+	})
+
+	return snapshot
+}
+
+// Write writes the snapshot to w as YAML
+func (s *Snapshot) Write(w io.Writer) error {
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// apiGroupDiscoveryList mirrors the aggregated discovery type served by the API Server at /api and
+// /apis (apidiscovery.k8s.io/v2beta1 and apidiscovery.k8s.io/v2)
+type apiGroupDiscoveryList struct {
+	Items []apiGroupDiscovery `json:"items"`
+}
+
+type apiGroupDiscovery struct {
+	Metadata metav1.ObjectMeta     `json:"metadata"`
+	Versions []apiVersionDiscovery `json:"versions"`
+}
+
+type apiVersionDiscovery struct {
+	Version   string                 `json:"version"`
+	Resources []apiResourceDiscovery `json:"resources"`
+}
+
+type apiResourceDiscovery struct {
+	Resource         string                   `json:"resource"`
+	ResponseKind     *metav1.GroupVersionKind `json:"responseKind"`
+	Scope            string                   `json:"scope"`
+	SingularResource string                   `json:"singularResource"`
+	Verbs            []string                 `json:"verbs"`
+	ShortNames       []string                 `json:"shortNames"`
+	Categories       []string                 `json:"categories"`
+}
+
+// toAPIResourceLists converts aggregated discovery information into the equivalent
+// APIResourceList for each group version
+func (l *apiGroupDiscoveryList) toAPIResourceLists() []metav1.APIResourceList {
+	var apiResourceLists []metav1.APIResourceList
+	for _, group := range l.Items {
+		for _, version := range group.Versions {
+			gv := schema.GroupVersion{Group: group.Metadata.Name, Version: version.Version}
+			apiResourceList := metav1.APIResourceList{
+				GroupVersion: gv.String(),
+			}
+			for _, resource := range version.Resources {
+				if resource.ResponseKind == nil {
+					continue
+				}
+				apiResourceList.APIResources = append(apiResourceList.APIResources, metav1.APIResource{
+					Name:         resource.Resource,
+					SingularName: resource.SingularResource,
+					Namespaced:   resource.Scope == namespacedScope,
+					Group:        resource.ResponseKind.Group,
+					Version:      resource.ResponseKind.Version,
+					Kind:         resource.ResponseKind.Kind,
+					Verbs:        resource.Verbs,
+					ShortNames:   resource.ShortNames,
+					Categories:   resource.Categories,
+				})
+			}
+			apiResourceLists = append(apiResourceLists, apiResourceList)
+		}
+	}
+	return apiResourceLists
+}
+
+// isDocumentFile returns true if the file should be parsed as JSON or YAML documents rather than
+// kubectl table output
+func isDocumentFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// readDocuments reads each JSON or YAML document in file
+func readDocuments(file string) ([]json.RawMessage, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []json.RawMessage
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+	for {
+		var document json.RawMessage
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return documents, fmt.Errorf("%s: document %d: %w", file, len(documents)+1, err)
+		}
+		// Skip empty documents
+		if len(document) == 0 || string(document) == "null" {
+			continue
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// parseAPIResourcesDocumentFile adds the resources in each DiscoverySnapshot, APIResourceList,
+// APIGroupDiscoveryList or OpenAPI document in file to discovery
+func (l *LocalResourceInspector) parseAPIResourcesDocumentFile(file string) error {
+	documents, err := readDocuments(file)
+	if err != nil {
+		return err
+	}
+
+	for i, document := range documents {
+		if isOpenAPIDocument(document) {
+			if err := l.addOpenAPIDocument(document); err != nil {
+				return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+			continue
+		}
+
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(document, &typeMeta); err != nil {
+			return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+		}
+
+		var apiResourceLists []metav1.APIResourceList
+		switch typeMeta.Kind {
+		case apiResourceListKind:
+			var apiResourceList metav1.APIResourceList
+			if err := json.Unmarshal(document, &apiResourceList); err != nil {
+				return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+			apiResourceLists = append(apiResourceLists, apiResourceList)
+		case SnapshotKind:
+			if typeMeta.APIVersion != SnapshotAPIVersion {
+				return fmt.Errorf("%s: document %d: unsupported %s version %q, expected %s", file, i+1, SnapshotKind, typeMeta.APIVersion, SnapshotAPIVersion)
+			}
+			var snapshot Snapshot
+			if err := json.Unmarshal(document, &snapshot); err != nil {
+				return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+			apiResourceLists = snapshot.Resources
+		case apiGroupDiscoveryListKind:
+			var apiGroupDiscoveryList apiGroupDiscoveryList
+			if err := json.Unmarshal(document, &apiGroupDiscoveryList); err != nil {
+				return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+			apiResourceLists = apiGroupDiscoveryList.toAPIResourceLists()
+		default:
+			return fmt.Errorf("%s: document %d: unsupported kind %q, expected %s, %s, %s or an OpenAPI document", file, i+1, typeMeta.Kind, SnapshotKind, apiResourceListKind, apiGroupDiscoveryListKind)
+		}
+
+		for _, apiResourceList := range apiResourceLists {
+			if err := l.addAPIResourceList(apiResourceList); err != nil {
+				return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseAPIVersionsDocumentFile adds each version in each APIGroupList document in file to the GVKs
+// in discovery belonging to the same group
+func (l *LocalResourceInspector) parseAPIVersionsDocumentFile(file string) error {
+	documents, err := readDocuments(file)
+	if err != nil {
+		return err
+	}
+
+	for i, document := range documents {
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(document, &typeMeta); err != nil {
+			return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+		}
+		if typeMeta.Kind != apiGroupListKind {
+			return fmt.Errorf("%s: document %d: unsupported kind %q, expected %s", file, i+1, typeMeta.Kind, apiGroupListKind)
+		}
+
+		var apiGroupList metav1.APIGroupList
+		if err := json.Unmarshal(document, &apiGroupList); err != nil {
+			return fmt.Errorf("%s: document %d: %w", file, i+1, err)
+		}
+		for _, group := range apiGroupList.Groups {
+			for _, version := range group.Versions {
+				l.addVersion(schema.GroupVersion{Group: group.Name, Version: version.Version})
+			}
+		}
+	}
+
+	return nil
+}
+
+// addAPIResourceList adds the scope and plural of each resource in apiResourceList to discovery
+func (l *LocalResourceInspector) addAPIResourceList(apiResourceList metav1.APIResourceList) error {
+	gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+	if err != nil {
+		return err
+	}
+
+	for _, apiResource := range apiResourceList.APIResources {
+		// Ignore subresources
+		if strings.Contains(apiResource.Name, "/") {
+			continue
+		}
+		if apiResource.Kind == "" {
+			return fmt.Errorf("kind is empty for resource %s in %s", apiResource.Name, apiResourceList.GroupVersion)
+		}
+
+		gvk := gv.WithKind(apiResource.Kind)
+		// Resources may override the group version of the list
+		if apiResource.Group != "" {
+			gvk.Group = apiResource.Group
+		}
+		if apiResource.Version != "" {
+			gvk.Version = apiResource.Version
+		}
+		l.AddGVKToScope(gvk, apiResource.Namespaced)
+		l.AddGVKToPlural(gvk, apiResource.Name)
+	}
+
+	return nil
+}
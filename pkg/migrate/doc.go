@@ -0,0 +1,3 @@
+// package migrate rewrites manifests that use deprecated or removed core APIs
+// to use the APIs that replace them.
+package migrate
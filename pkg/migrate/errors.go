@@ -0,0 +1,18 @@
+package migrate
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UnsupportedError is returned when a manifest cannot be migrated to the
+// replacement of its API automatically
+type UnsupportedError struct {
+	From schema.GroupVersionKind
+	To   schema.GroupVersionKind
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("migrating %s to %s is not supported", e.From.String(), e.To.String())
+}
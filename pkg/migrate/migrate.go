@@ -0,0 +1,387 @@
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dippynark/kfmt/pkg/discovery"
+	"github.com/dippynark/kfmt/pkg/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// converter applies the structural changes required to migrate a manifest to
+// the replacement of its API
+type converter func(node *yaml.RNode) error
+
+// converters are indexed by the GroupKind being migrated from. APIs without a
+// converter only require their apiVersion to be changed
+var converters = map[schema.GroupKind]converter{
+	{Group: "extensions", Kind: "Ingress"}:                  convertIngress,
+	{Group: "networking.k8s.io", Kind: "Ingress"}:           convertIngress,
+	{Group: "extensions", Kind: "Deployment"}:               convertWorkload,
+	{Group: "extensions", Kind: "DaemonSet"}:                convertWorkload,
+	{Group: "extensions", Kind: "ReplicaSet"}:               convertWorkload,
+	{Group: "apps", Kind: "Deployment"}:                     convertWorkload,
+	{Group: "apps", Kind: "DaemonSet"}:                      convertWorkload,
+	{Group: "apps", Kind: "ReplicaSet"}:                     convertWorkload,
+	{Group: "apps", Kind: "StatefulSet"}:                    convertWorkload,
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}: convertHorizontalPodAutoscaler,
+}
+
+// unsupported contains GroupKinds whose replacement requires changes that
+// cannot be made without additional information
+var unsupported = map[schema.GroupKind]struct{}{
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:               {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {},
+}
+
+// Migrate rewrites node to use the replacement of its API, following
+// replacements until an API without one is reached. If kubernetesVersion is
+// not nil, only APIs that are deprecated or removed in kubernetesVersion are
+// migrated. Migrate returns whether node was changed
+func Migrate(node *yaml.RNode, kubernetesVersion *utilversion.Version) (bool, error) {
+	gvk, err := utils.GetGVK(node)
+	if err != nil {
+		return false, err
+	}
+
+	migrated := false
+	for {
+		lifecycle, ok := discovery.GetAPILifecycle(gvk)
+		if !ok || lifecycle.Replacement.Empty() {
+			return migrated, nil
+		}
+		if kubernetesVersion != nil && !lifecycle.DeprecatedIn(kubernetesVersion) && !lifecycle.RemovedIn(kubernetesVersion) {
+			return migrated, nil
+		}
+		if !isSupported(gvk, lifecycle.Replacement) {
+			return migrated, &UnsupportedError{From: gvk, To: lifecycle.Replacement}
+		}
+
+		if convert := converters[gvk.GroupKind()]; convert != nil {
+			err = convert(node)
+			if err != nil {
+				return migrated, fmt.Errorf("failed to migrate %s to %s: %w", gvk.String(), lifecycle.Replacement.String(), err)
+			}
+		}
+
+		err = node.PipeE(yaml.SetField("apiVersion", yaml.NewScalarRNode(lifecycle.Replacement.GroupVersion().String())))
+		if err != nil {
+			return migrated, err
+		}
+		err = node.PipeE(yaml.SetField("kind", yaml.NewScalarRNode(lifecycle.Replacement.Kind)))
+		if err != nil {
+			return migrated, err
+		}
+
+		gvk = lifecycle.Replacement
+		migrated = true
+	}
+}
+
+// isSupported returns true if manifests can be migrated from one API to its
+// replacement. Replacements that change the kind are only supported by a
+// converter and a kind is never replaced by a list
+func isSupported(from, to schema.GroupVersionKind) bool {
+	if _, ok := unsupported[from.GroupKind()]; ok {
+		return false
+	}
+	if from.Kind == to.Kind {
+		return true
+	}
+	if strings.HasSuffix(to.Kind, "List") && !strings.HasSuffix(from.Kind, "List") {
+		return false
+	}
+	_, ok := converters[from.GroupKind()]
+	return ok
+}
+
+// convertIngress converts an extensions/v1beta1 or networking.k8s.io/v1beta1
+// Ingress to networking.k8s.io/v1
+func convertIngress(node *yaml.RNode) error {
+	err := moveField(node, []string{"spec", "backend"}, []string{"spec", "defaultBackend"})
+	if err != nil {
+		return err
+	}
+	err = convertIngressBackend(node, "spec", "defaultBackend")
+	if err != nil {
+		return err
+	}
+
+	rules, err := node.Pipe(yaml.Lookup("spec", "rules"))
+	if err != nil || rules == nil {
+		return err
+	}
+	return rules.VisitElements(func(rule *yaml.RNode) error {
+		paths, err := rule.Pipe(yaml.Lookup("http", "paths"))
+		if err != nil || paths == nil {
+			return err
+		}
+		return paths.VisitElements(func(path *yaml.RNode) error {
+			// pathType is required in networking.k8s.io/v1
+			pathType, err := path.Pipe(yaml.Lookup("pathType"))
+			if err != nil {
+				return err
+			}
+			if pathType == nil {
+				err = setField(path, "ImplementationSpecific", "pathType")
+				if err != nil {
+					return err
+				}
+			}
+			return convertIngressBackend(path, "backend")
+		})
+	})
+}
+
+// convertIngressBackend replaces the serviceName and servicePort fields of
+// the backend at path with the service field
+func convertIngressBackend(node *yaml.RNode, path ...string) error {
+	backend, err := node.Pipe(yaml.Lookup(path...))
+	if err != nil || backend == nil {
+		return err
+	}
+
+	err = moveField(backend, []string{"serviceName"}, []string{"service", "name"})
+	if err != nil {
+		return err
+	}
+
+	// Ports are referenced by number or name
+	servicePort, err := backend.Pipe(yaml.Lookup("servicePort"))
+	if err != nil || servicePort == nil {
+		return err
+	}
+	portField := "name"
+	if _, err := strconv.Atoi(servicePort.YNode().Value); err == nil {
+		portField = "number"
+	}
+	return moveField(backend, []string{"servicePort"}, []string{"service", "port", portField})
+}
+
+// convertWorkload converts a beta Deployment, DaemonSet, ReplicaSet or
+// StatefulSet to apps/v1
+func convertWorkload(node *yaml.RNode) error {
+	// spec.selector is required in apps/v1 and was previously defaulted to
+	// the Pod template labels
+	selector, err := node.Pipe(yaml.Lookup("spec", "selector"))
+	if err != nil {
+		return err
+	}
+	if selector == nil {
+		labels, err := node.Pipe(yaml.Lookup("spec", "template", "metadata", "labels"))
+		if err != nil {
+			return err
+		}
+		if labels != nil {
+			selector, err := node.Pipe(yaml.LookupCreate(yaml.MappingNode, "spec", "selector"))
+			if err != nil {
+				return err
+			}
+			err = selector.PipeE(yaml.SetField("matchLabels", labels.Copy()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Set the defaults of the beta API that differ from apps/v1 so that behaviour is unchanged
+	gvk, err := utils.GetGVK(node)
+	if err != nil {
+		return err
+	}
+	for _, d := range workloadDefaults[gvk] {
+		err = setDefault(node, d.value, d.path...)
+		if err != nil {
+			return err
+		}
+	}
+	if gvk == (schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}) {
+		strategyType, err := node.Pipe(yaml.Lookup("spec", "strategy", "type"))
+		if err != nil {
+			return err
+		}
+		if strategyType == nil || strategyType.YNode().Value == "RollingUpdate" {
+			err = setDefault(node, "1", "spec", "strategy", "rollingUpdate", "maxUnavailable")
+			if err != nil {
+				return err
+			}
+			err = setDefault(node, "1", "spec", "strategy", "rollingUpdate", "maxSurge")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Remove fields that do not exist in apps/v1
+	return clearFields(node, []string{"spec", "rollbackTo"}, []string{"spec", "templateGeneration"})
+}
+
+// fieldDefault is the default value of the field at path
+type fieldDefault struct {
+	path  []string
+	value string
+}
+
+// workloadDefaults contains the defaults of beta workload APIs that differ from those of apps/v1.
+// As with kubectl convert, unlimited values are written as the maximum 32-bit integer
+var workloadDefaults = map[schema.GroupVersionKind][]fieldDefault{
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}: {
+		{path: []string{"spec", "revisionHistoryLimit"}, value: "2147483647"},
+		{path: []string{"spec", "progressDeadlineSeconds"}, value: "2147483647"},
+	},
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}: {
+		{path: []string{"spec", "revisionHistoryLimit"}, value: "2"},
+	},
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}: {
+		{path: []string{"spec", "updateStrategy", "type"}, value: "OnDelete"},
+	},
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}: {
+		{path: []string{"spec", "updateStrategy", "type"}, value: "OnDelete"},
+	},
+}
+
+// convertHorizontalPodAutoscaler converts an autoscaling/v2beta1
+// HorizontalPodAutoscaler to autoscaling/v2. Later versions are structurally
+// identical
+func convertHorizontalPodAutoscaler(node *yaml.RNode) error {
+	apiVersion, err := utils.GetAPIVersion(node)
+	if err != nil || apiVersion != "autoscaling/v2beta1" {
+		return err
+	}
+
+	metrics, err := node.Pipe(yaml.Lookup("spec", "metrics"))
+	if err != nil || metrics == nil {
+		return err
+	}
+	return metrics.VisitElements(func(metric *yaml.RNode) error {
+		for _, source := range []string{"resource", "containerResource"} {
+			err := convertMetricTarget(metric, source, "targetAverageUtilization", "Utilization", "averageUtilization")
+			if err != nil {
+				return err
+			}
+			err = convertMetricTarget(metric, source, "targetAverageValue", "AverageValue", "averageValue")
+			if err != nil {
+				return err
+			}
+		}
+
+		err := moveFields(metric, [][2][]string{
+			{{"pods", "metricName"}, {"pods", "metric", "name"}},
+			{{"pods", "selector"}, {"pods", "metric", "selector"}},
+			{{"object", "target"}, {"object", "describedObject"}},
+			{{"object", "metricName"}, {"object", "metric", "name"}},
+			{{"object", "selector"}, {"object", "metric", "selector"}},
+			{{"external", "metricName"}, {"external", "metric", "name"}},
+			{{"external", "metricSelector"}, {"external", "metric", "selector"}},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, target := range [][3]string{
+			{"pods", "targetAverageValue", "AverageValue"},
+			{"object", "targetValue", "Value"},
+			{"object", "averageValue", "AverageValue"},
+			{"external", "targetValue", "Value"},
+			{"external", "targetAverageValue", "AverageValue"},
+		} {
+			targetField := "value"
+			if target[2] == "AverageValue" {
+				targetField = "averageValue"
+			}
+			err = convertMetricTarget(metric, target[0], target[1], target[2], targetField)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// convertMetricTarget moves the field of the metric source to the target
+// field of the same source, setting the target type
+func convertMetricTarget(metric *yaml.RNode, source, field, targetType, targetField string) error {
+	value, err := metric.Pipe(yaml.Lookup(source, field))
+	if err != nil || value == nil {
+		return err
+	}
+	err = moveField(metric, []string{source, field}, []string{source, "target", targetField})
+	if err != nil {
+		return err
+	}
+	return setField(metric, targetType, source, "target", "type")
+}
+
+// moveField moves the field at path from to path to, creating any missing
+// parent fields
+func moveField(node *yaml.RNode, from, to []string) error {
+	value, err := node.Pipe(yaml.Lookup(from...))
+	if err != nil || value == nil {
+		return err
+	}
+
+	err = clearFields(node, from)
+	if err != nil {
+		return err
+	}
+
+	parent, err := node.Pipe(yaml.LookupCreate(yaml.MappingNode, to[:len(to)-1]...))
+	if err != nil {
+		return err
+	}
+	return parent.PipeE(yaml.SetField(to[len(to)-1], value))
+}
+
+// moveFields moves each pair of from and to paths
+func moveFields(node *yaml.RNode, paths [][2][]string) error {
+	for _, path := range paths {
+		err := moveField(node, path[0], path[1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setField sets the field at path to value, creating any missing parent
+// fields
+func setField(node *yaml.RNode, value string, path ...string) error {
+	parent, err := node.Pipe(yaml.LookupCreate(yaml.MappingNode, path[:len(path)-1]...))
+	if err != nil {
+		return err
+	}
+	return parent.PipeE(yaml.SetField(path[len(path)-1], yaml.NewScalarRNode(value)))
+}
+
+// setDefault sets the field at path to value if it is not already set
+func setDefault(node *yaml.RNode, value string, path ...string) error {
+	field, err := node.Pipe(yaml.Lookup(path...))
+	if err != nil || field != nil {
+		return err
+	}
+	return setField(node, value, path...)
+}
+
+// clearFields removes the field at each path if it exists
+func clearFields(node *yaml.RNode, paths ...[]string) error {
+	for _, path := range paths {
+		parent, err := node.Pipe(yaml.Lookup(path[:len(path)-1]...))
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			continue
+		}
+		_, err = parent.Pipe(yaml.Clear(path[len(path)-1]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func requireMigrated(t *testing.T, input, expected string, kubernetesVersion *utilversion.Version, migrated bool) {
+	node, err := yaml.Parse(input)
+	require.Nil(t, err)
+	ok, err := Migrate(node, kubernetesVersion)
+	require.Nil(t, err)
+	require.Equal(t, migrated, ok)
+
+	// Compare with expected manifest formatted in the same way
+	expectedNode, err := yaml.Parse(expected)
+	require.Nil(t, err)
+	require.Equal(t, expectedNode.MustString(), node.MustString())
+}
+
+func TestMigrateIngress(t *testing.T) {
+	requireMigrated(t, `apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: example
+spec:
+  backend:
+    serviceName: default
+    servicePort: 80
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: example
+          servicePort: http
+      - path: /api
+        pathType: Prefix
+        backend:
+          serviceName: api
+          servicePort: 8080
+`, `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: example
+spec:
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          service:
+            name: example
+            port:
+              name: http
+        pathType: ImplementationSpecific
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: api
+            port:
+              number: 8080
+  defaultBackend:
+    service:
+      name: default
+      port:
+        number: 80
+`, nil, true)
+}
+
+func TestMigrateIngressClass(t *testing.T) {
+	requireMigrated(t, `apiVersion: networking.k8s.io/v1beta1
+kind: IngressClass
+metadata:
+  name: example
+spec:
+  controller: example.com/ingress-controller
+`, `apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: example
+spec:
+  controller: example.com/ingress-controller
+`, nil, true)
+}
+
+func TestMigrateWorkload(t *testing.T) {
+	// Ensure the defaults of extensions/v1beta1 Deployments are kept
+	requireMigrated(t, `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  rollbackTo:
+    revision: 1
+  template:
+    metadata:
+      labels:
+        app: example
+`, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  template:
+    metadata:
+      labels:
+        app: example
+  selector:
+    matchLabels:
+      app: example
+  revisionHistoryLimit: 2147483647
+  progressDeadlineSeconds: 2147483647
+  strategy:
+    rollingUpdate:
+      maxUnavailable: 1
+      maxSurge: 1
+`, nil, true)
+
+	// Ensure specified fields are not overridden and rolling update parameters are only set for
+	// rolling updates
+	requireMigrated(t, `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  revisionHistoryLimit: 5
+  strategy:
+    type: Recreate
+  selector:
+    matchLabels:
+      app: example
+`, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  revisionHistoryLimit: 5
+  strategy:
+    type: Recreate
+  selector:
+    matchLabels:
+      app: example
+  progressDeadlineSeconds: 2147483647
+`, nil, true)
+
+	// Ensure the defaults of apps/v1beta1 Deployments are kept
+	requireMigrated(t, `apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  selector:
+    matchLabels:
+      app: example
+`, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+spec:
+  selector:
+    matchLabels:
+      app: example
+  revisionHistoryLimit: 2
+`, nil, true)
+
+	// Ensure DaemonSets and StatefulSets are still updated on deletion
+	requireMigrated(t, `apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: example
+spec:
+  templateGeneration: 1
+  selector:
+    matchLabels:
+      app: example
+`, `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: example
+spec:
+  selector:
+    matchLabels:
+      app: example
+  updateStrategy:
+    type: OnDelete
+`, nil, true)
+	requireMigrated(t, `apiVersion: apps/v1beta1
+kind: StatefulSet
+metadata:
+  name: example
+spec:
+  selector:
+    matchLabels:
+      app: example
+`, `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: example
+spec:
+  selector:
+    matchLabels:
+      app: example
+  updateStrategy:
+    type: OnDelete
+`, nil, true)
+
+	// Ensure ReplicaSets, whose defaults are unchanged, only have their selector set
+	requireMigrated(t, `apiVersion: extensions/v1beta1
+kind: ReplicaSet
+metadata:
+  name: example
+spec:
+  template:
+    metadata:
+      labels:
+        app: example
+`, `apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: example
+spec:
+  template:
+    metadata:
+      labels:
+        app: example
+  selector:
+    matchLabels:
+      app: example
+`, nil, true)
+}
+
+func TestMigrateHorizontalPodAutoscaler(t *testing.T) {
+	requireMigrated(t, `apiVersion: autoscaling/v2beta1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: example
+spec:
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      targetAverageUtilization: 80
+  - type: Pods
+    pods:
+      metricName: requests
+      targetAverageValue: 1k
+  - type: Object
+    object:
+      target:
+        kind: Service
+        name: example
+      metricName: requests
+      targetValue: 10k
+  - type: External
+    external:
+      metricName: queue
+      metricSelector:
+        matchLabels:
+          queue: example
+      targetAverageValue: "30"
+`, `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: example
+spec:
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      target:
+        averageUtilization: 80
+        type: Utilization
+  - type: Pods
+    pods:
+      metric:
+        name: requests
+      target:
+        averageValue: 1k
+        type: AverageValue
+  - type: Object
+    object:
+      describedObject:
+        kind: Service
+        name: example
+      metric:
+        name: requests
+      target:
+        value: 10k
+        type: Value
+  - type: External
+    external:
+      metric:
+        name: queue
+        selector:
+          matchLabels:
+            queue: example
+      target:
+        averageValue: "30"
+        type: AverageValue
+`, nil, true)
+}
+
+func TestMigrateKubernetesVersion(t *testing.T) {
+	cronJob := `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: example
+`
+
+	// Ensure APIs are not migrated before they are deprecated
+	requireMigrated(t, cronJob, cronJob, utilversion.MustParseGeneric("1.20"), false)
+
+	// Ensure deprecated APIs are migrated
+	requireMigrated(t, cronJob, `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: example
+`, utilversion.MustParseGeneric("1.21"), true)
+
+	// Ensure current APIs are not migrated
+	requireMigrated(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+`, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: example
+`, nil, false)
+}
+
+func TestMigrateUnsupported(t *testing.T) {
+	node, err := yaml.Parse(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: testers.test.io
+`)
+	require.Nil(t, err)
+	_, err = Migrate(node, nil)
+	require.EqualError(t, err, "migrating apiextensions.k8s.io/v1beta1, Kind=CustomResourceDefinition to apiextensions.k8s.io/v1, Kind=CustomResourceDefinition is not supported")
+}
+
+func TestIsSupported(t *testing.T) {
+	ingressClass := schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}
+	require.True(t, isSupported(ingressClass, schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"}))
+
+	// Ensure kinds are not replaced by lists or by other kinds without a converter
+	require.False(t, isSupported(ingressClass, schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressClassList"}))
+	require.False(t, isSupported(ingressClass, schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "GatewayClass"}))
+	require.False(t, isSupported(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "IngressList"}))
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -11,6 +14,16 @@ import (
 
 var quotes = []string{"'", "\""}
 
+// Kinds of discovery documents served by the API Server or written by kfmt that have an apiVersion
+// and kind but are not manifests
+var discoveryKinds = map[string]struct{}{
+	"APIGroupList":          {},
+	"APIResourceList":       {},
+	"APIVersions":           {},
+	"APIGroupDiscoveryList": {},
+	"DiscoverySnapshot":     {},
+}
+
 func GetAnnotations(node *yaml.RNode) (map[string]string, error) {
 	annotations := map[string]string{}
 
@@ -104,6 +117,19 @@ func GetCRDKind(node *yaml.RNode) (string, error) {
 	return kind, nil
 }
 
+func GetCRDPlural(node *yaml.RNode) (string, error) {
+	plural, err := GetStringField(node, "spec", "names", "plural")
+	if err != nil {
+		return "", err
+	}
+
+	if plural == "" {
+		return "", errors.New("CRD plural is empty")
+	}
+
+	return plural, nil
+}
+
 func GetCRDScope(node *yaml.RNode) (string, error) {
 	scope, err := GetStringField(node, "spec", "scope")
 	if err != nil {
@@ -211,3 +237,175 @@ func GetGVK(node *yaml.RNode) (gvk schema.GroupVersionKind, err error) {
 
 	return gvk, nil
 }
+
+// ExpandLists replaces each List (e.g. v1 List or ConfigMapList) in nodes with its items. Items of
+// typed lists that omit apiVersion or kind are given the list's apiVersion and the kind of the list
+// without the List suffix
+func ExpandLists(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	var expandedNodes []*yaml.RNode
+	for _, node := range nodes {
+		kind, err := GetStringField(node, "kind")
+		if err != nil {
+			return expandedNodes, err
+		}
+		items, err := node.Pipe(yaml.Lookup("items"))
+		if err != nil {
+			return expandedNodes, err
+		}
+		if !strings.HasSuffix(kind, "List") || items == nil || items.YNode().Kind != yaml.SequenceNode {
+			expandedNodes = append(expandedNodes, node)
+			continue
+		}
+
+		apiVersion, err := GetStringField(node, "apiVersion")
+		if err != nil {
+			return expandedNodes, err
+		}
+		itemKind := strings.TrimSuffix(kind, "List")
+
+		var itemNodes []*yaml.RNode
+		for _, item := range items.Content() {
+			itemNode := yaml.NewRNode(item)
+			if kind != "List" {
+				if err := setDefaultTypeMeta(itemNode, apiVersion, itemKind); err != nil {
+					return expandedNodes, err
+				}
+			}
+			itemNodes = append(itemNodes, itemNode)
+		}
+
+		// Lists may contain other lists
+		itemNodes, err = ExpandLists(itemNodes)
+		if err != nil {
+			return expandedNodes, err
+		}
+		expandedNodes = append(expandedNodes, itemNodes...)
+	}
+
+	return expandedNodes, nil
+}
+
+// setDefaultTypeMeta sets the apiVersion and kind fields of node if they are not already set.
+// Missing fields are added before any other fields
+func setDefaultTypeMeta(node *yaml.RNode, apiVersion, kind string) error {
+	if node.YNode().Kind != yaml.MappingNode {
+		return errors.New("list item is not a mapping")
+	}
+
+	var typeMeta []*yaml.Node
+	for _, field := range []struct{ name, value string }{{"apiVersion", apiVersion}, {"kind", kind}} {
+		current, err := GetStringField(node, field.name)
+		if err != nil {
+			return err
+		}
+		if current == "" {
+			// Remove empty field before it is added
+			if err := node.PipeE(yaml.Clear(field.name)); err != nil {
+				return err
+			}
+			typeMeta = append(typeMeta, yaml.NewScalarRNode(field.name).YNode(), yaml.NewScalarRNode(field.value).YNode())
+		}
+	}
+	node.YNode().Content = append(typeMeta, node.YNode().Content...)
+
+	return nil
+}
+
+// IsJSON returns true if input looks like a JSON object or array, ignoring leading whitespace
+func IsJSON(input []byte) bool {
+	trimmed := bytes.TrimSpace(input)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// ParseJSON parses a JSON object, an array of objects or a stream of objects such as
+// newline-delimited JSON into nodes. Nodes are styled as block YAML so that they are written as YAML
+func ParseJSON(input []byte) ([]*yaml.RNode, error) {
+	var nodes []*yaml.RNode
+	values, err := decodeJSONValues(input)
+	if err != nil {
+		return nodes, err
+	}
+
+	for _, value := range values {
+		// JSON is valid YAML so is parsed as YAML to preserve field order
+		node, err := yaml.Parse(string(value))
+		if err != nil {
+			return nodes, err
+		}
+		if node.YNode().Kind != yaml.MappingNode {
+			return nodes, errors.Errorf("expected JSON object but found %s", strings.TrimSpace(string(value)))
+		}
+		clearJSONStyle(node.YNode())
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// IsJSONManifests returns true if input is a JSON object, an array of objects or a stream of objects
+// that each have an apiVersion and kind, such as Kubernetes manifests and Lists. Other JSON documents
+// such as package.json, OpenAPI documents and discovery documents are not manifests
+func IsJSONManifests(input []byte) bool {
+	if !IsJSON(input) {
+		return false
+	}
+	values, err := decodeJSONValues(input)
+	if err != nil || len(values) == 0 {
+		return false
+	}
+
+	for _, value := range values {
+		var typeMeta struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}
+		if err := json.Unmarshal(value, &typeMeta); err != nil || typeMeta.APIVersion == "" || typeMeta.Kind == "" {
+			return false
+		}
+		if _, ok := discoveryKinds[typeMeta.Kind]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeJSONValues decodes a JSON value or a stream of values. The elements of top-level arrays are
+// returned individually
+func decodeJSONValues(input []byte) ([]json.RawMessage, error) {
+	var values []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return values, err
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			var elements []json.RawMessage
+			if err := json.Unmarshal(value, &elements); err != nil {
+				return values, err
+			}
+			values = append(values, elements...)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// clearJSONStyle removes the flow and quoting style of JSON nodes. Strings that would be parsed as
+// other types by YAML 1.1 parsers remain quoted
+func clearJSONStyle(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Tag != yaml.NodeTagString || !yaml.IsYaml1_1NonString(node) {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		clearJSONStyle(child)
+	}
+}
@@ -313,3 +313,170 @@ kind: CustomResourceDefinition
         t.Error("expected error due to missing CRD scope")
     }
 }
+
+func TestGetCRDPlural(t *testing.T) {
+    manifests := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  names:
+    plural: testerz
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+`
+    nodes, err := kio.FromBytes([]byte(manifests))
+    if err != nil {
+        t.Error(err)
+    }
+
+    if len(nodes) != 2 {
+        t.Error("failed to ingest manifests")
+    }
+
+    crdPlural, err := GetCRDPlural(nodes[0])
+    if err != nil {
+        t.Error(err)
+    }
+
+    if crdPlural != "testerz" {
+        t.Error("failed to retrieve CRD plural")
+    }
+
+    crdPlural, err = GetCRDPlural(nodes[1])
+    if err == nil {
+        t.Error("expected error due to missing CRD plural")
+    }
+}
+
+func TestExpandLists(t *testing.T) {
+    manifests := `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: test
+- apiVersion: v1
+  kind: ConfigMapList
+  items:
+  - metadata:
+      name: test
+      namespace: test
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+`
+    nodes, err := kio.FromBytes([]byte(manifests))
+    if err != nil {
+        t.Error(err)
+    }
+
+    if len(nodes) != 2 {
+        t.Error("failed to ingest manifests")
+    }
+
+    nodes, err = ExpandLists(nodes)
+    if err != nil {
+        t.Error(err)
+    }
+
+    if len(nodes) != 3 {
+        t.Fatal("failed to expand lists")
+    }
+
+    for i, expected := range []string{"Namespace", "ConfigMap", "Secret"} {
+        kind, err := GetKind(nodes[i])
+        if err != nil {
+            t.Error(err)
+        }
+        if kind != expected {
+            t.Error(fmt.Sprintf("expected kind %s but found %s", expected, kind))
+        }
+    }
+
+    configMap, err := nodes[1].String()
+    if err != nil {
+        t.Error(err)
+    }
+    if configMap != "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n  namespace: test\n" {
+        t.Error("failed to set type of typed list item")
+    }
+}
+
+func TestParseJSON(t *testing.T) {
+    manifests := `[
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "test"}, "data": {"enabled": "true", "replicas": "1", "on": "on", "list": "[a, b]"}}
+]
+{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "test", "labels": {}}}
+{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "test"}, "stringData": {"multiline": "a\nb"}}
+`
+    if !IsJSON([]byte(manifests)) {
+        t.Error("failed to detect JSON")
+    }
+    if IsJSON([]byte("apiVersion: v1\nkind: Namespace\n")) {
+        t.Error("failed to detect YAML")
+    }
+
+    nodes, err := ParseJSON([]byte(manifests))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(nodes) != 3 {
+        t.Fatal("failed to ingest manifests")
+    }
+
+    configMap, err := nodes[0].String()
+    if err != nil {
+        t.Error(err)
+    }
+    if configMap != `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+data:
+  enabled: "true"
+  replicas: "1"
+  "on": "on"
+  list: "[a, b]"
+` {
+        t.Error(fmt.Sprintf("unexpected YAML:\n%s", configMap))
+    }
+
+    namespace, err := nodes[1].String()
+    if err != nil {
+        t.Error(err)
+    }
+    if namespace != "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test\n  labels: {}\n" {
+        t.Error(fmt.Sprintf("unexpected YAML:\n%s", namespace))
+    }
+
+    _, err = ParseJSON([]byte(`["test"]`))
+    if err == nil {
+        t.Error("expected error due to JSON value that is not an object")
+    }
+}
+
+
+func TestIsJSONManifests(t *testing.T) {
+    for input, expected := range map[string]bool{
+        `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "test"}}`:                       true,
+        `[{"apiVersion": "v1", "kind": "Namespace"}]` + "\n" + `{"apiVersion": "v1", "kind": "Secret"}`: true,
+        `{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "v1", "kind": "Namespace"}]}`:    true,
+        `{"name": "test", "version": "1.0.0"}`:                                                          false,
+        `[{"apiVersion": "v1", "kind": "Namespace"}, {"name": "test"}]`:                                 false,
+        `{"kind": "APIResourceList", "apiVersion": "v1", "groupVersion": "v1", "resources": []}`:        false,
+        `{"apiVersion": "kfmt.dev/v1alpha1", "kind": "DiscoverySnapshot", "resources": []}`:             false,
+        `[]`:                                false,
+        `{"apiVersion": "v1"`:               false,
+        "apiVersion: v1\nkind: Namespace\n": false,
+    } {
+        if IsJSONManifests([]byte(input)) != expected {
+            t.Error(fmt.Sprintf("expected IsJSONManifests to return %t for %s", expected, input))
+        }
+    }
+}